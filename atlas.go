@@ -0,0 +1,90 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Name of the checksum file of an Atlas migration directory
+const ATLAS_SUM_FILE = "atlas.sum"
+
+// AtlasWriter saves migrations as "<version>_<name>.sql" files and keeps the "atlas.sum" checksum file
+// of the directory up to date, the versioned migration format of Atlas.
+// Atlas plans down migrations itself, so down scripts are not saved
+type AtlasWriter struct{}
+
+var _ MigrationWriter = AtlasWriter{}
+
+// Fails if the migration files were changed after "atlas.sum" is written, like Atlas does
+func (AtlasWriter) ReadMigrations(dir string) ([]Migration, error) {
+	saved, err := os.ReadFile(filepath.Join(dir, ATLAS_SUM_FILE))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		sum, err := atlasSum(dir)
+		if err != nil {
+			return nil, err
+		}
+		if sum != string(saved) {
+			return nil, fmt.Errorf("checksum mismatch in %s, migration files are changed. Run 'atlas migrate hash' to accept the changes", filepath.Join(dir, ATLAS_SUM_FILE))
+		}
+	}
+
+	return readSingleFileMigrations(dir, func(path, content string) (string, string, error) {
+		return content, "", nil
+	})
+}
+
+func (AtlasWriter) WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s.sql", migration.Version, migration.Name))
+	paths, err := writeFiles([]outputFile{{Path: path, Content: migration.Up}}, overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	sum, err := atlasSum(dir)
+	if err != nil {
+		return nil, err
+	}
+	sumPath := filepath.Join(dir, ATLAS_SUM_FILE)
+	if err := os.WriteFile(sumPath, []byte(sum), 0644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", ATLAS_SUM_FILE, err)
+	}
+	return append(paths, sumPath), nil
+}
+
+// Returns the content of the "atlas.sum" file of the directory, computed the way Atlas' 'migrate.HashFile' does.
+// Every ".sql" file is hashed in name order, the hash of a file is the sha256 of the names and contents of the files
+// up to and including it. The sum on the first line is the sha256 of the names and hashes of the files
+func atlasSum(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return "", err
+	}
+
+	fileHash := sha256.New()
+	sumHash := sha256.New()
+	var sb strings.Builder
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		name := filepath.Base(path)
+		fileHash.Write([]byte(name))
+		fileHash.Write(content)
+		hash := base64.StdEncoding.EncodeToString(fileHash.Sum(nil))
+
+		sumHash.Write([]byte(name))
+		sumHash.Write([]byte(hash))
+		sb.WriteString(fmt.Sprintf("%s h1:%s\n", name, hash))
+	}
+
+	return fmt.Sprintf("h1:%s\n%s", base64.StdEncoding.EncodeToString(sumHash.Sum(nil)), sb.String()), nil
+}
@@ -0,0 +1,49 @@
+package migrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Sum of the files below by the algorithm of Atlas' 'migrate.HashFile', computed independently of 'atlasSum'
+const testAtlasSum = `h1:lxMKMZ000sU0k2Fyu362eRYOs9Et6WIeoDDTbC8eEOg=
+1_init.sql h1:t9/ZVRELZ0UDSr7yPTgIdQpsXlqPZNtBMYoE4P0Rrd4=
+2_add_name.sql h1:x25LzFqPeE62PZXkkGJYs52lJEVjIWsw0IGblXy2N5Q=
+`
+
+func TestAtlasWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := AtlasWriter{}
+	for _, migration := range []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE users (id int);\n"},
+		{Version: 2, Name: "add_name", Up: "ALTER TABLE users ADD COLUMN name varchar(255);\n"},
+	} {
+		if _, err := writer.WriteMigration(dir, migration, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sum, err := os.ReadFile(filepath.Join(dir, ATLAS_SUM_FILE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(sum) != testAtlasSum {
+		t.Errorf("atlas.sum is\n%s\nwant\n%s", sum, testAtlasSum)
+	}
+
+	migrations, err := writer.ReadMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[1].Name != "add_name" {
+		t.Errorf("read migrations %+v", migrations)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "1_init.sql"), []byte("CREATE TABLE users (id bigint);\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.ReadMigrations(dir); err == nil {
+		t.Error("changed migration file is read without a checksum error")
+	}
+}
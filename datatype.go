@@ -0,0 +1,185 @@
+package migrator
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MigratorDataTyper is implemented by field types that choose their own column type. e.g.
+//
+//	func (UUID) MigratorDataType(dialect migrator.Dialect) string {
+//		if dialect.Name() == "postgres" {
+//			return "uuid"
+//		}
+//		return "char(36)"
+//	}
+//
+// An empty type falls back to the other rules of 'Migrator.dataType'
+type MigratorDataTyper interface {
+	MigratorDataType(dialect Dialect) string
+}
+
+// gorm's 'schema.GormDataTypeInterface'. e.g. "json" of 'datatypes.JSON'
+type gormDataTyper interface {
+	GormDataType() string
+}
+
+// gorm's general data types and the go types they are mapped by. Other gorm data types are used as the column type
+var gormGeneralTypes = map[string]string{
+	"bool":   "bool",
+	"int":    "int64",
+	"uint":   "uint64",
+	"float":  "float64",
+	"string": "string",
+	"time":   "time.Time",
+	"bytes":  "[]byte",
+}
+
+// Well known types whose values don't tell their column type, and the dialect types they are mapped by.
+// e.g. 'decimal.Decimal' stores its values as strings
+var knownDataTypes = map[string]string{
+	"github.com/shopspring/decimal.Decimal": "decimal",
+	"github.com/google/uuid.UUID":           "uuid",
+	"github.com/gofrs/uuid.UUID":            "uuid",
+	"github.com/gofrs/uuid/v5.UUID":         "uuid",
+	"github.com/satori/go.uuid.UUID":        "uuid",
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// Registers the column type of the go type of the value, so fields of that type get the column type of every dialect.
+// Pointers are registered as the type they point to. e.g. RegisterDataType(uuid.UUID{}, "char(36)")
+func (m *Migrator) RegisterDataType(value interface{}, columnType string) {
+	typ := reflect.TypeOf(value)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if m.DataTypes == nil {
+		m.DataTypes = make(map[reflect.Type]string)
+	}
+	m.DataTypes[typ] = columnType
+}
+
+// Returns the column type of the go type of a field. Pointers are the type they point to, they are nullable like every column
+// without 'not null'. The first rule that gives a type is used:
+//   - the type registered by 'RegisterDataType'
+//   - 'MigratorDataTyper'
+//   - gorm's 'GormDBDataType(*gorm.DB, *schema.Field) string', called with 'Migrator.GormDB' if it is set
+//   - gorm's 'GormDataType() string', gorm's general types like "string" and "time" are mapped by the dialect
+//   - well known types, 'decimal.Decimal' is a decimal column and 'uuid.UUID' is a uuid column on postgres
+//   - 'driver.Valuer' types are the type of their zero value's value, the way gorm maps them. e.g. int64 for 'type Level int8'.
+//     Valuers of a null value are their first struct field, so 'sql.NullInt64' is int64 and 'gorm.DeletedAt' is time.Time.
+//     Struct and array valuers that store strings fail, since the string doesn't tell what the column holds
+//   - named types are their underlying kind. e.g. string for 'type Status string', '[]byte' for 'json.RawMessage'
+func (m *Migrator) dataType(typ reflect.Type) (string, error) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if columnType, ok := m.DataTypes[typ]; ok {
+		return columnType, nil
+	}
+
+	// Pointer to a zero value has the methods of both value and pointer receivers
+	value := reflect.New(typ)
+	if typer, ok := value.Interface().(MigratorDataTyper); ok {
+		if columnType := typer.MigratorDataType(m.Dialect); columnType != "" {
+			return columnType, nil
+		}
+	}
+	if columnType, err := m.gormDBDataType(value); columnType != "" || err != nil {
+		return columnType, err
+	}
+	if typer, ok := value.Interface().(gormDataTyper); ok {
+		if dataType := typer.GormDataType(); dataType != "" {
+			if goType, ok := gormGeneralTypes[dataType]; ok {
+				return m.Dialect.DataType(goType)
+			}
+			return dataType, nil
+		}
+	}
+	if goType, ok := knownDataTypes[typ.PkgPath()+"."+typ.Name()]; ok {
+		return m.Dialect.DataType(goType)
+	}
+	if valueType, ok := valueGoType(value); ok && valueType != typ {
+		if valueType.Kind() == reflect.String && (typ.Kind() == reflect.Struct || typ.Kind() == reflect.Array) {
+			return "", fmt.Errorf("%s stores its values as strings, set a 'type' tag or register its column type by 'RegisterDataType'", typ)
+		}
+		return m.dataType(valueType)
+	}
+
+	switch {
+	case typ == timeType || typ.ConvertibleTo(timeType):
+		return m.Dialect.DataType("time.Time")
+	case (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() == reflect.Uint8:
+		return m.Dialect.DataType("[]byte")
+	case typ.Kind() <= reflect.Float64 || typ.Kind() == reflect.String:
+		// Bool, numbers and strings, also named ones. e.g. 'type Status string'
+		return m.Dialect.DataType(typ.Kind().String())
+	}
+	return m.Dialect.DataType(typ.String())
+}
+
+// Returns true if the type is a column type instead of a struct to flatten or a relation. e.g. time.Time.
+// Valuers are column types even if their column type must be given by a tag
+func (m *Migrator) isDataType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	_, err := m.dataType(typ)
+	return err == nil || reflect.PointerTo(typ).Implements(valuerType)
+}
+
+// Returns the result of gorm's 'GormDBDataType(*gorm.DB, *schema.Field) string' method of the value.
+// gorm is not imported, so the method is found by its name and called with 'Migrator.GormDB' and an empty field.
+// Returns an empty type if the value has no such method or 'Migrator.GormDB' is not set
+func (m *Migrator) gormDBDataType(value reflect.Value) (columnType string, err error) {
+	method := value.MethodByName("GormDBDataType")
+	if !method.IsValid() || m.GormDB == nil {
+		return "", nil
+	}
+	methodType := method.Type()
+	db := reflect.ValueOf(m.GormDB)
+	if methodType.NumIn() != 2 || methodType.NumOut() != 1 || methodType.Out(0).Kind() != reflect.String ||
+		!db.Type().AssignableTo(methodType.In(0)) || methodType.In(1).Kind() != reflect.Pointer {
+
+		return "", nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("GormDBDataType of %s failed: %v", value.Type().Elem(), r)
+		}
+	}()
+	field := reflect.New(methodType.In(1).Elem())
+	return method.Call([]reflect.Value{db, field})[0].String(), nil
+}
+
+// Returns the go type of the value a 'driver.Valuer' stores for its zero value.
+// Null values are stored as the type of the first field of the struct. e.g. string for 'sql.NullString'.
+// False if the value is not a valuer, or its value cannot be read
+func valueGoType(value reflect.Value) (typ reflect.Type, ok bool) {
+	valuer, isValuer := value.Interface().(driver.Valuer)
+	if !isValuer {
+		return nil, false
+	}
+
+	defer func() {
+		if recover() != nil {
+			typ, ok = nil, false
+		}
+	}()
+	if v, err := valuer.Value(); err == nil && v != nil {
+		return reflect.TypeOf(v), true
+	}
+
+	structType := value.Type().Elem()
+	if structType.Kind() != reflect.Struct || structType.NumField() == 0 {
+		return nil, false
+	}
+	return structType.Field(0).Type, true
+}
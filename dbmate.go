@@ -0,0 +1,24 @@
+package migrator
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// DbmateWriter saves migrations as a single "<version>_<name>.sql" file
+// with "-- migrate:up" and "-- migrate:down" annotations, the format of github.com/amacneil/dbmate
+type DbmateWriter struct{}
+
+var _ MigrationWriter = DbmateWriter{}
+
+func (DbmateWriter) ReadMigrations(dir string) ([]Migration, error) {
+	return readSingleFileMigrations(dir, func(path, content string) (string, string, error) {
+		return splitAnnotated(path, content, "-- migrate:up", "-- migrate:down")
+	})
+}
+
+func (DbmateWriter) WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error) {
+	content := fmt.Sprintf("-- migrate:up\n%s\n-- migrate:down\n%s", migration.Up, migration.Down)
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s.sql", migration.Version, migration.Name))
+	return writeFiles([]outputFile{{Path: path, Content: content}}, overwrite)
+}
@@ -0,0 +1,451 @@
+package ddl
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"strconv"
+	"strings"
+)
+
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.i+offset >= len(p.tokens) {
+		return token{kind: EOF_TOKEN, start: len(p.src), end: len(p.src)}
+	}
+	return p.tokens[p.i+offset]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.i >= len(p.tokens)
+}
+
+// Consumes the given keywords if the next tokens match all of them, case insensitive
+func (p *parser) keyword(words ...string) bool {
+	for j, w := range words {
+		t := p.peekAt(j)
+		if t.kind != IDENT_TOKEN || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	p.i += len(words)
+	return true
+}
+
+// Returns true if the next token is one of the given keywords, without consuming it
+func (p *parser) isKeyword(words ...string) bool {
+	t := p.peek()
+	if t.kind != IDENT_TOKEN {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Consumes the given symbol if it is the next token
+func (p *parser) symbol(s string) bool {
+	t := p.peek()
+	if t.kind == SYMBOL_TOKEN && t.text == s {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) isSymbol(s string) bool {
+	t := p.peek()
+	return t.kind == SYMBOL_TOKEN && t.text == s
+}
+
+func (p *parser) expectKeyword(words ...string) error {
+	if !p.keyword(words...) {
+		return p.errorf("expected %s", strings.Join(words, " "))
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(s string) error {
+	if !p.symbol(s) {
+		return p.errorf("expected '%s'", s)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	near := "end of statement"
+	if t.kind != EOF_TOKEN {
+		near = fmt.Sprintf("%q", p.src[t.start:t.end])
+	}
+	return fmt.Errorf("%s near %s", fmt.Sprintf(format, args...), near)
+}
+
+// Parses a quoted or unquoted identifier
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t.kind != IDENT_TOKEN && t.kind != QUOTED_IDENT_TOKEN {
+		return "", p.errorf("expected identifier")
+	}
+	p.i++
+	return t.text, nil
+}
+
+// Parses a table name. Schema qualified names are resolved to the table name
+func (p *parser) tableName() (string, error) {
+	name, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	if p.symbol(".") {
+		return p.ident()
+	}
+	return name, nil
+}
+
+// Consumes a balanced parenthesized expression and returns its source, parentheses included
+func (p *parser) parens() (string, error) {
+	start := p.peek()
+	if err := p.expectSymbol("("); err != nil {
+		return "", err
+	}
+	for depth := 1; depth > 0; {
+		t := p.next()
+		switch {
+		case t.kind == EOF_TOKEN:
+			return "", p.errorf("unbalanced parentheses")
+		case t.kind == SYMBOL_TOKEN && t.text == "(":
+			depth++
+		case t.kind == SYMBOL_TOKEN && t.text == ")":
+			depth--
+		}
+	}
+	return p.src[start.start:p.tokens[p.i-1].end], nil
+}
+
+// Consumes tokens until the next ',' or ')' that is not nested in parentheses
+func (p *parser) skipClause() {
+	for depth := 0; !p.done(); {
+		t := p.peek()
+		if t.kind == SYMBOL_TOKEN {
+			switch t.text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					return
+				}
+			}
+		}
+		p.i++
+	}
+}
+
+// Parses an index column list. e.g. "(name(10) DESC, email)"
+func (p *parser) indexColumns() ([]schema.IndexColumn, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	var cols []schema.IndexColumn
+	for {
+		if p.isSymbol("(") {
+			return nil, p.errorf("functional index parts are not supported")
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		col := schema.IndexColumn{Name: name, Priority: len(cols)}
+
+		if p.symbol("(") {
+			t := p.next()
+			if t.kind != NUMBER_TOKEN {
+				return nil, p.errorf("expected prefix length")
+			}
+			col.Length, _ = strconv.Atoi(t.text)
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.keyword("DESC") {
+			col.Desc = true
+		} else {
+			p.keyword("ASC")
+		}
+
+		cols = append(cols, col)
+		if !p.symbol(",") {
+			break
+		}
+	}
+
+	return cols, p.expectSymbol(")")
+}
+
+// Parses a column name list. e.g. "(id, name)"
+func (p *parser) columnNames() ([]string, error) {
+	cols, err := p.indexColumns()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+func (p *parser) referenceOption() (schema.ReferenceOption, error) {
+	switch {
+	case p.keyword("RESTRICT"):
+		return schema.RESTRICT_OPTION, nil
+	case p.keyword("CASCADE"):
+		return schema.CASCADE_OPTION, nil
+	case p.keyword("SET", "NULL"):
+		return schema.SET_NULL_OPTION, nil
+	case p.keyword("NO", "ACTION"):
+		return schema.NO_ACTION_OPTION, nil
+	case p.keyword("SET", "DEFAULT"):
+		return schema.SET_DEFAULT_OPTION, nil
+	}
+	return "", p.errorf("expected reference option")
+}
+
+// Parses a column type the way DESCRIBE reports it. e.g. "varchar(255)", "bigint unsigned"
+func (p *parser) columnType() (string, error) {
+	base, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	columnType := strings.ToLower(base)
+
+	// Multi word types
+	if strings.EqualFold(base, "double") && p.keyword("PRECISION") {
+		columnType = "double"
+	}
+
+	if p.isSymbol("(") {
+		args, err := p.parens()
+		if err != nil {
+			return "", err
+		}
+		columnType += strings.ReplaceAll(args, " ", "")
+	}
+
+	for {
+		switch {
+		case p.keyword("UNSIGNED"):
+			columnType += " unsigned"
+		case p.keyword("ZEROFILL"):
+			columnType += " zerofill"
+		case p.keyword("SIGNED"):
+		default:
+			return columnType, nil
+		}
+	}
+}
+
+// Parses a DEFAULT value and returns its source. NULL means the column has no default value
+func (p *parser) defaultValue() (sql.NullString, error) {
+	start := p.peek()
+	switch {
+	case start.kind == EOF_TOKEN:
+		return sql.NullString{}, p.errorf("expected default value")
+	case p.isSymbol("("):
+		expr, err := p.parens()
+		return sql.NullString{String: expr, Valid: true}, err
+	case p.isSymbol("-") || p.isSymbol("+"):
+		p.next()
+		if t := p.next(); t.kind != NUMBER_TOKEN {
+			return sql.NullString{}, p.errorf("expected number")
+		}
+	case p.keyword("NULL"):
+		return sql.NullString{}, nil
+	default:
+		t := p.next()
+		if t.kind == IDENT_TOKEN && p.isSymbol("(") { // Function call. e.g. CURRENT_TIMESTAMP(3)
+			if _, err := p.parens(); err != nil {
+				return sql.NullString{}, err
+			}
+		}
+	}
+	return sql.NullString{String: p.src[start.start:p.tokens[p.i-1].end], Valid: true}, nil
+}
+
+// Parses the DEFAULT value of a column of the given type into the form of 'schema.NormalizeDefault',
+// so it is compared with the defaults of the models the same way. Values that can't be normalized are kept as they are
+func (p *parser) columnDefault(columnType string) (sql.NullString, error) {
+	value, err := p.defaultValue()
+	if err != nil || !value.Valid {
+		return value, err
+	}
+	if normalized, err := schema.NormalizeDefault(columnType, value.String, false); err == nil {
+		return normalized, nil
+	}
+	return value, nil
+}
+
+type columnDefinition struct {
+	column     *schema.Column
+	primaryKey bool           // Declared by the inline PRIMARY KEY attribute
+	unique     bool           // Declared by the inline UNIQUE attribute
+	checks     []schema.Check // Declared by the inline CHECK attributes, unnamed ones have no name yet
+}
+
+// Parses a column definition. e.g. "name varchar(255) NOT NULL DEFAULT 'x'"
+func (p *parser) columnDefinition(tableName string) (*columnDefinition, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	columnType, err := p.columnType()
+	if err != nil {
+		return nil, err
+	}
+
+	def := &columnDefinition{column: &schema.Column{
+		TableName:  tableName,
+		Name:       name,
+		ColumnType: columnType,
+		Null:       "YES",
+	}}
+	col := def.column
+
+	for !p.done() && !p.isSymbol(",") && !p.isSymbol(")") && !p.isKeyword("FIRST", "AFTER") {
+		switch {
+		case p.keyword("NOT", "NULL"):
+			col.Null = "NO"
+		case p.keyword("NULL"):
+			col.Null = "YES"
+		case p.keyword("DEFAULT"):
+			if col.DefaultValue, err = p.columnDefault(col.ColumnType); err != nil {
+				return nil, err
+			}
+		case p.keyword("AUTO_INCREMENT"):
+			col.Extra = strings.TrimSpace(col.Extra + " auto_increment")
+		case p.keyword("PRIMARY", "KEY"):
+			def.primaryKey = true
+		case p.keyword("KEY"):
+			def.primaryKey = true
+		case p.keyword("UNIQUE"):
+			def.unique = true
+			p.keyword("KEY")
+		case p.keyword("COMMENT"):
+			comment := p.next()
+			if comment.kind != STRING_TOKEN {
+				return nil, p.errorf("expected column comment")
+			}
+			col.Comment = stringValue(comment)
+		case p.keyword("COLLATE"), p.keyword("CHARSET"), p.keyword("CHARACTER", "SET"),
+			p.keyword("COLUMN_FORMAT"), p.keyword("STORAGE"), p.keyword("SRID"):
+			p.next()
+		case p.keyword("ON", "UPDATE"):
+			if _, err := p.defaultValue(); err != nil {
+				return nil, err
+			}
+		case p.keyword("VISIBLE"), p.keyword("INVISIBLE"), p.keyword("VIRTUAL"), p.keyword("STORED"):
+		case p.keyword("GENERATED", "ALWAYS", "AS"), p.keyword("AS"):
+			if _, err := p.parens(); err != nil {
+				return nil, err
+			}
+		case p.keyword("CONSTRAINT"):
+			// Name of an inline check constraint
+			name := ""
+			if !p.isKeyword("CHECK") {
+				if name, err = p.ident(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectKeyword("CHECK"); err != nil {
+				return nil, err
+			}
+			check, err := p.check(name)
+			if err != nil {
+				return nil, err
+			}
+			def.checks = append(def.checks, check)
+		case p.keyword("CHECK"):
+			check, err := p.check("")
+			if err != nil {
+				return nil, err
+			}
+			def.checks = append(def.checks, check)
+		case p.keyword("REFERENCES"):
+			// Inline references are parsed but ignored by MySQL
+			p.skipClause()
+		default:
+			return nil, p.errorf("unsupported column attribute")
+		}
+	}
+
+	if def.primaryKey {
+		col.PrimaryKey = true
+		col.Null = "NO"
+	}
+	return def, nil
+}
+
+// Parses the condition of a check constraint after the CHECK keyword. e.g. "(age > 13) NOT ENFORCED"
+func (p *parser) check(name string) (schema.Check, error) {
+	condition, err := p.parens()
+	if err != nil {
+		return schema.Check{}, err
+	}
+	if !p.keyword("NOT", "ENFORCED") {
+		p.keyword("ENFORCED")
+	}
+	return schema.Check{Name: name, Expression: strings.TrimSpace(condition[1 : len(condition)-1])}, nil
+}
+
+// Returns the value of a string token, doubled quotes and backslash escapes are unescaped
+func stringValue(t token) string {
+	var sb strings.Builder
+	for i := 0; i < len(t.text); i++ {
+		c := t.text[i]
+		switch {
+		case c == '\\' && i+1 < len(t.text):
+			i++
+			switch c = t.text[i]; c {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'r':
+				c = '\r'
+			case '0':
+				c = 0
+			}
+		case (c == '\'' || c == '"') && i+1 < len(t.text) && t.text[i+1] == c:
+			i++
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lexical rules of a dialect that decide where its statements end
+type splitSyntax struct {
+	dollarQuotes   bool // $tag$ strings and E'...' strings with backslash escapes, PostgreSQL
+	nestedComments bool // '/*' in a block comment opens another comment, PostgreSQL
+	bracketIdents  bool // `identifier` and [identifier], SQLite
+}
+
+var (
+	postgresSyntax = splitSyntax{dollarQuotes: true, nestedComments: true}
+	sqliteSyntax   = splitSyntax{bracketIdents: true}
+)
+
+// Splits the given PostgreSQL script into statements by ';', ignoring the ';' in strings, identifiers, comments,
+// dollar quoted bodies and 'BEGIN ATOMIC ... END' function bodies. Backslashes only escape in E'...' strings.
+// Comments between statements and empty statements are dropped
+func SplitPostgresStatements(script string) ([]string, error) {
+	return splitScript(script, postgresSyntax)
+}
+
+// Splits the given SQLite script into statements by ';', ignoring the ';' in strings, identifiers, comments
+// and 'CREATE TRIGGER ... BEGIN ... END' bodies. Backslashes never escape.
+// Comments between statements and empty statements are dropped
+func SplitSQLiteStatements(script string) ([]string, error) {
+	return splitScript(script, sqliteSyntax)
+}
+
+// Splits the script by the given syntax. Statements are returned without the comments around them and without ';'
+func splitScript(script string, syntax splitSyntax) ([]string, error) {
+	var statements []string
+	start, end := -1, 0 // Source of the current statement, start is -1 until the statement has a token
+
+	// Statements that create a trigger or a function may have a body of statements,
+	// their ';' don't end the statement until the BEGIN and CASE blocks are closed by END
+	var words int
+	var create, routine bool
+	depth := 0
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+
+		case strings.HasPrefix(script[i:], "--"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			continue
+
+		case strings.HasPrefix(script[i:], "/*"):
+			j, err := skipBlockComment(script, i, syntax.nestedComments)
+			if err != nil {
+				return nil, err
+			}
+			i = j
+			continue
+
+		case c == ';' && depth == 0:
+			if start != -1 {
+				statements = append(statements, script[start:end])
+			}
+			start = -1
+			words, create, routine = 0, false, false
+			i++
+			continue
+		}
+
+		if start == -1 {
+			start = i
+		}
+
+		var err error
+		switch {
+		case c == '\'':
+			i, err = skipQuoted(script, i, '\'', false)
+		case c == '"':
+			i, err = skipQuoted(script, i, '"', false)
+		case syntax.bracketIdents && c == '`':
+			i, err = skipQuoted(script, i, '`', false)
+		case syntax.bracketIdents && c == '[':
+			i, err = skipQuoted(script, i, ']', false)
+		case syntax.dollarQuotes && c == '$' && (i == 0 || !isIdentChar(script[i-1])):
+			i, err = skipDollarQuoted(script, i)
+
+		case isIdentChar(c) && !isDigit(c):
+			j := i
+			for j < len(script) && isIdentChar(script[j]) {
+				j++
+			}
+			word := strings.ToUpper(script[i:j])
+
+			// E'...' strings of PostgreSQL escape by backslashes
+			if syntax.dollarQuotes && word == "E" && j < len(script) && script[j] == '\'' {
+				i, err = skipQuoted(script, j, '\'', true)
+				break
+			}
+			i = j
+
+			switch {
+			case words == 0:
+				create = word == "CREATE"
+			case create && words < 5 && (word == "TRIGGER" || word == "FUNCTION" || word == "PROCEDURE"):
+				// e.g. "CREATE OR REPLACE CONSTRAINT TRIGGER", later words may be column names
+				routine = true
+			case routine && (word == "BEGIN" || word == "CASE"):
+				depth++
+			case routine && word == "END" && depth > 0:
+				depth--
+			}
+			words++
+
+		default:
+			i++
+		}
+		if err != nil {
+			return nil, err
+		}
+		end = i
+	}
+
+	if start != -1 {
+		statements = append(statements, script[start:end])
+	}
+	return statements, nil
+}
+
+// Returns the position after the block comment that starts at i
+func skipBlockComment(script string, i int, nested bool) (int, error) {
+	depth := 0
+	for j := i; j < len(script)-1; j++ {
+		switch {
+		case script[j] == '/' && script[j+1] == '*' && (nested || depth == 0):
+			depth++
+			j++
+		case script[j] == '*' && script[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated comment at %d", i)
+}
+
+// Returns the position after the quoted string or identifier that starts at i and ends with the closing character.
+// Closing character is escaped by doubling it, and also by a backslash if backslash is true
+func skipQuoted(script string, i int, closing byte, backslash bool) (int, error) {
+	for j := i + 1; j < len(script); j++ {
+		switch {
+		case backslash && script[j] == '\\':
+			j++
+		case script[j] == closing:
+			if j+1 < len(script) && script[j+1] == closing {
+				j++
+				continue
+			}
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at %d", i)
+}
+
+// Returns the position after the dollar quoted string that starts at i. e.g. $$body$$ or $fn$body$fn$.
+// A '$' that doesn't start a tag, like the '$1' parameter, is a single character
+func skipDollarQuoted(script string, i int) (int, error) {
+	j := i + 1
+	for j < len(script) && isIdentChar(script[j]) && script[j] != '$' {
+		j++
+	}
+	if j >= len(script) || script[j] != '$' || (j > i+1 && isDigit(script[i+1])) {
+		return i + 1, nil
+	}
+
+	tag := script[i : j+1]
+	end := strings.Index(script[j+1:], tag)
+	if end == -1 {
+		return 0, fmt.Errorf("unterminated dollar quoted string at %d", i)
+	}
+	return j + 1 + end + len(tag), nil
+}
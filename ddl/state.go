@@ -0,0 +1,885 @@
+package ddl
+
+import (
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+	"strings"
+)
+
+// State is a schema built by replaying MySQL DDL statements, without a database.
+// Tables are kept the way the MySQL introspection of migrator reports them
+type State struct {
+	tables    []*schema.Table
+	fkIndexes map[string][]string // table -> indexes created implicitly by foreign keys
+}
+
+// ParseError is returned when a statement cannot be replayed
+type ParseError struct {
+	Statement string
+	Err       error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v in statement: %s", e.Err, e.Statement)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func NewState() *State {
+	return &State{
+		fkIndexes: make(map[string][]string),
+	}
+}
+
+// Returns the tables of the state ordered by name
+func (s *State) Tables() []*schema.Table {
+	tables := slices.Clone(s.tables)
+	slices.SortFunc(tables, func(a, b *schema.Table) int { return strings.Compare(a.Name, b.Name) })
+	return tables
+}
+
+// Applies every statement of the given script to the state.
+// Statements that don't change the schema, like INSERT, are skipped
+func (s *State) Apply(script string) error {
+	tokens, err := tokenize(script)
+	if err != nil {
+		return &ParseError{Statement: script, Err: err}
+	}
+
+	for _, statement := range splitStatements(tokens) {
+		src := script[statement[0].start:statement[len(statement)-1].end]
+		p := &parser{src: script, tokens: statement}
+		if err := s.apply(p); err != nil {
+			return &ParseError{Statement: src, Err: err}
+		}
+		if !p.done() {
+			return &ParseError{Statement: src, Err: p.errorf("unexpected token")}
+		}
+	}
+	return nil
+}
+
+func (s *State) apply(p *parser) error {
+	switch {
+	case p.keyword("CREATE", "TABLE"):
+		return s.createTable(p)
+	case p.keyword("CREATE", "INDEX"):
+		return s.createIndex(p, false, "")
+	case p.keyword("CREATE", "UNIQUE"):
+		p.keyword("INDEX")
+		return s.createIndex(p, true, "")
+	case p.keyword("CREATE", "FULLTEXT"), p.keyword("CREATE", "SPATIAL"):
+		indexType := schema.IndexType(strings.ToUpper(p.tokens[p.i-1].text))
+		p.keyword("INDEX")
+		return s.createIndex(p, false, indexType)
+	case p.keyword("DROP", "TABLE"):
+		return s.dropTable(p)
+	case p.keyword("DROP", "INDEX"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expectKeyword("ON"); err != nil {
+			return err
+		}
+		t, err := s.table(p)
+		if err != nil {
+			return err
+		}
+		return s.dropIndex(t, name)
+	case p.keyword("ALTER", "TABLE"):
+		return s.alterTable(p)
+	case p.keyword("RENAME", "TABLE"):
+		for {
+			t, err := s.table(p)
+			if err != nil {
+				return err
+			}
+			if err := p.expectKeyword("TO"); err != nil {
+				return err
+			}
+			newName, err := p.tableName()
+			if err != nil {
+				return err
+			}
+			s.renameTable(t, newName)
+			if !p.symbol(",") {
+				return nil
+			}
+		}
+	}
+
+	// Not a schema change, skip the statement
+	p.i = len(p.tokens)
+	return nil
+}
+
+func (s *State) getTable(name string) *schema.Table {
+	for _, t := range s.tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Parses a table name and returns the existing table
+func (s *State) table(p *parser) (*schema.Table, error) {
+	name, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	t := s.getTable(name)
+	if t == nil {
+		return nil, fmt.Errorf("table %s does not exist", name)
+	}
+	return t, nil
+}
+
+func getColumn(t *schema.Table, name string) *schema.Column {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *State) createTable(p *parser) error {
+	ifNotExists := p.keyword("IF", "NOT", "EXISTS")
+	name, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	if s.getTable(name) != nil {
+		if ifNotExists {
+			p.i = len(p.tokens)
+			return nil
+		}
+		return fmt.Errorf("table %s already exists", name)
+	}
+
+	t := &schema.Table{Name: name, IndexToUniqueCols: make(map[string][]string)}
+	if err := p.expectSymbol("("); err != nil {
+		return err
+	}
+	for {
+		if err := s.addTableElement(p, t, true); err != nil {
+			return err
+		}
+		if !p.symbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return err
+	}
+
+	// Table options. e.g. ENGINE=InnoDB. Only the comment is kept
+	for !p.done() {
+		if p.isKeyword("COMMENT") {
+			if err := tableComment(p, t); err != nil {
+				return err
+			}
+			continue
+		}
+		p.next()
+	}
+
+	s.tables = append(s.tables, t)
+	return nil
+}
+
+// Parses a column, index or constraint definition of CREATE TABLE and ALTER TABLE ... ADD
+func (s *State) addTableElement(p *parser, t *schema.Table, isCreate bool) error {
+	var constraintName string
+	if p.keyword("CONSTRAINT") {
+		if !p.isKeyword("PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			constraintName = name
+		}
+	}
+
+	switch {
+	case p.keyword("PRIMARY", "KEY"):
+		p.keyword("USING", "BTREE")
+		cols, err := p.columnNames()
+		if err != nil {
+			return err
+		}
+		return s.setPrimaryKey(t, cols)
+
+	case p.keyword("UNIQUE"):
+		if !p.keyword("INDEX") {
+			p.keyword("KEY")
+		}
+		name := constraintName
+		if !p.isSymbol("(") && !p.isKeyword("USING") {
+			indexName, err := p.ident()
+			if err != nil {
+				return err
+			}
+			name = indexName
+		}
+		return s.addIndex(p, t, name, true, "")
+
+	case p.keyword("FOREIGN", "KEY"):
+		if !p.isSymbol("(") {
+			if _, err := p.ident(); err != nil {
+				return err
+			}
+		}
+		return s.addForeignKey(p, t, constraintName)
+
+	case p.keyword("CHECK"):
+		check, err := p.check(constraintName)
+		if err != nil {
+			return err
+		}
+		return addCheck(t, check)
+
+	case p.keyword("INDEX"), p.keyword("KEY"):
+		return s.addIndex(p, t, "", false, "")
+
+	case p.keyword("FULLTEXT"), p.keyword("SPATIAL"):
+		indexType := schema.IndexType(strings.ToUpper(p.tokens[p.i-1].text))
+		if !p.keyword("INDEX") {
+			p.keyword("KEY")
+		}
+		return s.addIndex(p, t, "", false, indexType)
+	}
+
+	if constraintName != "" {
+		return p.errorf("expected constraint definition")
+	}
+
+	if !isCreate {
+		p.keyword("COLUMN")
+		if p.isSymbol("(") {
+			return p.errorf("adding multiple columns in parentheses is not supported")
+		}
+	}
+
+	def, err := p.columnDefinition(t.Name)
+	if err != nil {
+		return err
+	}
+	if getColumn(t, def.column.Name) != nil {
+		return fmt.Errorf("column %s.%s already exists", t.Name, def.column.Name)
+	}
+	skipColumnPosition(p)
+
+	t.Columns = append(t.Columns, def.column)
+	return s.applyInlineKeys(t, def)
+}
+
+// Applies the inline PRIMARY KEY, UNIQUE and CHECK attributes of a column definition
+func (s *State) applyInlineKeys(t *schema.Table, def *columnDefinition) error {
+	for _, check := range def.checks {
+		if err := addCheck(t, check); err != nil {
+			return err
+		}
+	}
+	if def.primaryKey {
+		if err := s.setPrimaryKey(t, []string{def.column.Name}); err != nil {
+			return err
+		}
+	}
+	if def.unique {
+		name := def.column.Name
+		for i := 2; hasIndex(t, name); i++ {
+			name = fmt.Sprintf("%s_%d", def.column.Name, i)
+		}
+		return putIndex(t, &schema.Index{Name: name, Unique: true, Columns: []schema.IndexColumn{{Name: def.column.Name}}})
+	}
+	return nil
+}
+
+// Adds the check constraint to the table. Unnamed checks get the name MySQL generates. e.g. users_chk_1
+func addCheck(t *schema.Table, check schema.Check) error {
+	hasCheck := func(name string) bool {
+		return slices.ContainsFunc(t.Checks, func(c schema.Check) bool { return strings.EqualFold(c.Name, name) })
+	}
+	if check.Name == "" {
+		check.Name = fmt.Sprintf("%s_chk_1", t.Name)
+		for i := 2; hasCheck(check.Name); i++ {
+			check.Name = fmt.Sprintf("%s_chk_%d", t.Name, i)
+		}
+	}
+	if hasCheck(check.Name) {
+		return fmt.Errorf("check constraint %s already exists on %s", check.Name, t.Name)
+	}
+	t.Checks = append(t.Checks, check)
+	return nil
+}
+
+// Returns true if the table has an index or unique constraint with the given name
+func hasIndex(t *schema.Table, name string) bool {
+	_, exists := t.IndexToUniqueCols[name]
+	return exists || t.GetIndex(name) != nil
+}
+
+// Adds the index to the table. Unique constraints are kept separately from the other indexes,
+// the same way the introspection of migrator reports them
+func putIndex(t *schema.Table, index *schema.Index) error {
+	if hasIndex(t, index.Name) {
+		return fmt.Errorf("index %s already exists on %s", index.Name, t.Name)
+	}
+	if !index.IsUniqueConstraint() {
+		t.Indexes = append(t.Indexes, index)
+		return nil
+	}
+
+	t.IndexToUniqueCols[index.Name] = index.ColumnNames()
+	if len(index.Columns) == 1 {
+		getColumn(t, index.Columns[0].Name).UniqueIndex = true
+	}
+	return nil
+}
+
+// FIRST and AFTER don't change the schema for the migrator
+func skipColumnPosition(p *parser) {
+	if p.keyword("FIRST") {
+		return
+	}
+	if p.keyword("AFTER") {
+		p.next()
+	}
+}
+
+func (s *State) setPrimaryKey(t *schema.Table, cols []string) error {
+	if len(t.PrimaryCols) > 0 {
+		return fmt.Errorf("table %s already has a primary key", t.Name)
+	}
+	for _, name := range cols {
+		c := getColumn(t, name)
+		if c == nil {
+			return fmt.Errorf("primary key column %s.%s does not exist", t.Name, name)
+		}
+		c.PrimaryKey = true
+		c.Null = "NO"
+		t.PrimaryCols = append(t.PrimaryCols, c.Name)
+	}
+	return nil
+}
+
+// Parses the rest of an index definition, starting from the optional index name
+func (s *State) addIndex(p *parser, t *schema.Table, name string, unique bool, indexType schema.IndexType) error {
+	if name == "" && !p.isSymbol("(") && !p.isKeyword("USING") {
+		indexName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		name = indexName
+	}
+
+	if p.keyword("USING") {
+		indexType = schema.IndexType(strings.ToUpper(p.next().text))
+	}
+	cols, err := p.indexColumns()
+	if err != nil {
+		return err
+	}
+	if p.keyword("USING") {
+		indexType = schema.IndexType(strings.ToUpper(p.next().text))
+	}
+	// Index options. e.g. COMMENT 'x'
+	p.skipClause()
+
+	for _, c := range cols {
+		if getColumn(t, c.Name) == nil {
+			return fmt.Errorf("index column %s.%s does not exist", t.Name, c.Name)
+		}
+	}
+
+	// Unnamed indexes are named after their first column
+	if name == "" {
+		name = cols[0].Name
+		for i := 2; hasIndex(t, name); i++ {
+			name = fmt.Sprintf("%s_%d", cols[0].Name, i)
+		}
+	}
+
+	return putIndex(t, &schema.Index{Name: name, Columns: cols, Unique: unique, Type: indexType})
+}
+
+func (s *State) createIndex(p *parser, unique bool, indexType schema.IndexType) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if p.keyword("USING") {
+		indexType = schema.IndexType(strings.ToUpper(p.next().text))
+	}
+	if err := p.expectKeyword("ON"); err != nil {
+		return err
+	}
+	t, err := s.table(p)
+	if err != nil {
+		return err
+	}
+	return s.addIndex(p, t, name, unique, indexType)
+}
+
+// Parses the rest of a foreign key definition, starting from the column list
+func (s *State) addForeignKey(p *parser, t *schema.Table, name string) error {
+	cols, err := p.columnNames()
+	if err != nil {
+		return err
+	}
+	if err := p.expectKeyword("REFERENCES"); err != nil {
+		return err
+	}
+	referencedTable, err := p.tableName()
+	if err != nil {
+		return err
+	}
+	referencedCols, err := p.columnNames()
+	if err != nil {
+		return err
+	}
+	if len(cols) != 1 || len(referencedCols) != 1 {
+		return fmt.Errorf("composite foreign keys are not supported")
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("%s_ibfk_%d", t.Name, len(t.References)+1)
+	}
+	if slices.ContainsFunc(t.References, func(r schema.Reference) bool { return r.Name == name }) {
+		return fmt.Errorf("foreign key %s already exists on %s", name, t.Name)
+	}
+
+	reference := schema.Reference{
+		Name:                 name,
+		TableName:            t.Name,
+		ColumnName:           cols[0],
+		ReferencedTableName:  referencedTable,
+		ReferencedColumnName: referencedCols[0],
+		DeleteOption:         schema.NO_ACTION_OPTION,
+		UpdateOption:         schema.NO_ACTION_OPTION,
+	}
+	for {
+		if p.keyword("ON", "DELETE") {
+			if reference.DeleteOption, err = p.referenceOption(); err != nil {
+				return err
+			}
+		} else if p.keyword("ON", "UPDATE") {
+			if reference.UpdateOption, err = p.referenceOption(); err != nil {
+				return err
+			}
+		} else if p.keyword("MATCH") {
+			p.next()
+		} else {
+			break
+		}
+	}
+
+	c := getColumn(t, reference.ColumnName)
+	if c == nil {
+		return fmt.Errorf("foreign key column %s.%s does not exist", t.Name, reference.ColumnName)
+	}
+	c.ForeignKey = true
+
+	t.References = append(t.References, reference)
+
+	// MySQL creates an index named after the constraint, unless the column is already indexed first
+	if !s.isIndexedFirst(t, reference.ColumnName) {
+		s.fkIndexes[t.Name] = append(s.fkIndexes[t.Name], name)
+	}
+	return nil
+}
+
+func (s *State) isIndexedFirst(t *schema.Table, col string) bool {
+	if len(t.PrimaryCols) > 0 && strings.EqualFold(t.PrimaryCols[0], col) {
+		return true
+	}
+	for _, cols := range t.IndexToUniqueCols {
+		if strings.EqualFold(cols[0], col) {
+			return true
+		}
+	}
+	for _, index := range t.Indexes {
+		if strings.EqualFold(index.Columns[0].Name, col) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *State) dropTable(p *parser) error {
+	ifExists := p.keyword("IF", "EXISTS")
+	for {
+		name, err := p.tableName()
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(s.tables, func(t *schema.Table) bool { return t.Name == name })
+		if i == -1 && !ifExists {
+			return fmt.Errorf("table %s does not exist", name)
+		}
+		if i != -1 {
+			s.tables = slices.Delete(s.tables, i, i+1)
+			delete(s.fkIndexes, name)
+		}
+		if !p.symbol(",") {
+			break
+		}
+	}
+	p.keyword("RESTRICT")
+	p.keyword("CASCADE")
+	return nil
+}
+
+func (s *State) dropIndex(t *schema.Table, name string) error {
+	if i := slices.Index(s.fkIndexes[t.Name], name); i != -1 {
+		s.fkIndexes[t.Name] = slices.Delete(s.fkIndexes[t.Name], i, i+1)
+		return nil
+	}
+	if i := slices.IndexFunc(t.Indexes, func(index *schema.Index) bool { return index.Name == name }); i != -1 {
+		t.Indexes = slices.Delete(t.Indexes, i, i+1)
+		return nil
+	}
+	if cols, exists := t.IndexToUniqueCols[name]; exists {
+		delete(t.IndexToUniqueCols, name)
+		if len(cols) == 1 {
+			if c := getColumn(t, cols[0]); c != nil {
+				c.UniqueIndex = false
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("index %s does not exist on %s", name, t.Name)
+}
+
+func dropPrimaryKey(t *schema.Table) {
+	for _, c := range t.Columns {
+		c.PrimaryKey = false
+	}
+	t.PrimaryCols = nil
+}
+
+func (s *State) dropForeignKey(t *schema.Table, name string) error {
+	i := slices.IndexFunc(t.References, func(r schema.Reference) bool { return r.Name == name })
+	if i == -1 {
+		return fmt.Errorf("foreign key %s does not exist on %s", name, t.Name)
+	}
+	col := t.References[i].ColumnName
+	t.References = slices.Delete(t.References, i, i+1)
+	if c := getColumn(t, col); c != nil {
+		c.ForeignKey = slices.ContainsFunc(t.References, func(r schema.Reference) bool { return r.ColumnName == col })
+	}
+	return nil
+}
+
+func (s *State) dropColumn(t *schema.Table, name string) error {
+	i := slices.IndexFunc(t.Columns, func(c *schema.Column) bool { return strings.EqualFold(c.Name, name) })
+	if i == -1 {
+		return fmt.Errorf("column %s.%s does not exist", t.Name, name)
+	}
+	name = t.Columns[i].Name
+	t.Columns = slices.Delete(t.Columns, i, i+1)
+	t.PrimaryCols = slices.DeleteFunc(t.PrimaryCols, func(c string) bool { return c == name })
+
+	// Dropped column is removed from the indexes, indexes without columns are dropped
+	for key, cols := range t.IndexToUniqueCols {
+		cols = slices.DeleteFunc(cols, func(c string) bool { return c == name })
+		if len(cols) == 0 {
+			delete(t.IndexToUniqueCols, key)
+		} else {
+			t.IndexToUniqueCols[key] = cols
+		}
+	}
+	for _, index := range t.Indexes {
+		index.Columns = slices.DeleteFunc(index.Columns, func(c schema.IndexColumn) bool { return c.Name == name })
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(index *schema.Index) bool { return len(index.Columns) == 0 })
+	return nil
+}
+
+func (s *State) renameColumn(t *schema.Table, oldName, newName string) error {
+	c := getColumn(t, oldName)
+	if c == nil {
+		return fmt.Errorf("column %s.%s does not exist", t.Name, oldName)
+	}
+	oldName = c.Name
+	c.Name = newName
+
+	rename := func(name string) string {
+		if name == oldName {
+			return newName
+		}
+		return name
+	}
+	for i, pk := range t.PrimaryCols {
+		t.PrimaryCols[i] = rename(pk)
+	}
+	for _, cols := range t.IndexToUniqueCols {
+		for i, col := range cols {
+			cols[i] = rename(col)
+		}
+	}
+	for _, index := range t.Indexes {
+		for i := range index.Columns {
+			index.Columns[i].Name = rename(index.Columns[i].Name)
+		}
+	}
+	for i := range t.References {
+		t.References[i].ColumnName = rename(t.References[i].ColumnName)
+	}
+
+	// References to the renamed column are updated by MySQL
+	for _, other := range s.tables {
+		for i, r := range other.References {
+			if r.ReferencedTableName == t.Name && r.ReferencedColumnName == oldName {
+				other.References[i].ReferencedColumnName = newName
+			}
+		}
+	}
+	return nil
+}
+
+func (s *State) renameTable(t *schema.Table, newName string) {
+	oldName := t.Name
+	t.Name = newName
+	for _, c := range t.Columns {
+		c.TableName = newName
+	}
+	for _, other := range s.tables {
+		for i, r := range other.References {
+			if r.TableName == oldName {
+				other.References[i].TableName = newName
+			}
+			if r.ReferencedTableName == oldName {
+				other.References[i].ReferencedTableName = newName
+			}
+		}
+	}
+	s.fkIndexes[newName] = s.fkIndexes[oldName]
+	delete(s.fkIndexes, oldName)
+}
+
+// Replaces the definition of a column, keys of the column are kept
+func (s *State) modifyColumn(t *schema.Table, oldName string, def *columnDefinition) error {
+	c := getColumn(t, oldName)
+	if c == nil {
+		return fmt.Errorf("column %s.%s does not exist", t.Name, oldName)
+	}
+	if !strings.EqualFold(c.Name, def.column.Name) {
+		if err := s.renameColumn(t, c.Name, def.column.Name); err != nil {
+			return err
+		}
+	}
+
+	c.ColumnType = def.column.ColumnType
+	c.DefaultValue = def.column.DefaultValue
+	c.Extra = def.column.Extra
+	c.Null = def.column.Null
+	c.Comment = def.column.Comment
+	if c.PrimaryKey {
+		c.Null = "NO"
+	}
+
+	def.column = c
+	return s.applyInlineKeys(t, def)
+}
+
+func (s *State) alterTable(p *parser) error {
+	t, err := s.table(p)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := s.alterSpecification(p, t); err != nil {
+			return err
+		}
+		if !p.symbol(",") {
+			return nil
+		}
+	}
+}
+
+func (s *State) alterSpecification(p *parser, t *schema.Table) error {
+	switch {
+	case p.keyword("ADD"):
+		return s.addTableElement(p, t, false)
+
+	case p.keyword("DROP", "PRIMARY", "KEY"):
+		dropPrimaryKey(t)
+		return nil
+
+	case p.keyword("DROP", "FOREIGN", "KEY"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return s.dropForeignKey(t, name)
+
+	case p.keyword("DROP", "INDEX"), p.keyword("DROP", "KEY"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return s.dropIndex(t, name)
+
+	case p.keyword("DROP", "CONSTRAINT"), p.keyword("DROP", "CHECK"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if i := slices.IndexFunc(t.Checks, func(c schema.Check) bool { return strings.EqualFold(c.Name, name) }); i != -1 {
+			t.Checks = slices.Delete(t.Checks, i, i+1)
+			return nil
+		}
+		if slices.ContainsFunc(t.References, func(r schema.Reference) bool { return r.Name == name }) {
+			return s.dropForeignKey(t, name)
+		}
+		// Postgres names the primary key "<table>_pkey"
+		if strings.EqualFold(name, t.Name+"_pkey") && len(t.PrimaryCols) > 0 {
+			dropPrimaryKey(t)
+			return nil
+		}
+		if err := s.dropIndex(t, name); err == nil {
+			return nil
+		}
+		return fmt.Errorf("constraint %s does not exist on %s", name, t.Name)
+
+	case p.keyword("DROP"):
+		p.keyword("COLUMN")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return s.dropColumn(t, name)
+
+	case p.keyword("MODIFY"):
+		p.keyword("COLUMN")
+		def, err := p.columnDefinition(t.Name)
+		if err != nil {
+			return err
+		}
+		skipColumnPosition(p)
+		return s.modifyColumn(t, def.column.Name, def)
+
+	case p.keyword("CHANGE"):
+		p.keyword("COLUMN")
+		oldName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		def, err := p.columnDefinition(t.Name)
+		if err != nil {
+			return err
+		}
+		skipColumnPosition(p)
+		return s.modifyColumn(t, oldName, def)
+
+	case p.keyword("RENAME", "COLUMN"):
+		oldName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expectKeyword("TO"); err != nil {
+			return err
+		}
+		newName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return s.renameColumn(t, oldName, newName)
+
+	case p.keyword("RENAME", "INDEX"), p.keyword("RENAME", "KEY"):
+		oldName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expectKeyword("TO"); err != nil {
+			return err
+		}
+		newName, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return s.renameIndex(t, oldName, newName)
+
+	case p.keyword("RENAME"):
+		if !p.keyword("TO") {
+			p.keyword("AS")
+		}
+		newName, err := p.tableName()
+		if err != nil {
+			return err
+		}
+		s.renameTable(t, newName)
+		return nil
+
+	case p.keyword("ALTER"):
+		p.keyword("COLUMN")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		c := getColumn(t, name)
+		if c == nil {
+			return fmt.Errorf("column %s.%s does not exist", t.Name, name)
+		}
+		if p.keyword("DROP", "DEFAULT") {
+			c.DefaultValue.Valid = false
+			c.DefaultValue.String = ""
+			return nil
+		}
+		if err := p.expectKeyword("SET", "DEFAULT"); err != nil {
+			return err
+		}
+		c.DefaultValue, err = p.columnDefault(c.ColumnType)
+		return err
+
+	case p.isKeyword("COMMENT"):
+		return tableComment(p, t)
+
+	case p.isKeyword("ENGINE", "AUTO_INCREMENT", "CHARACTER", "CHARSET", "COLLATE",
+		"ALGORITHM", "LOCK", "ROW_FORMAT", "DEFAULT", "CONVERT", "FORCE"):
+		// Table options don't change the schema for the migrator
+		p.skipClause()
+		return nil
+	}
+
+	return p.errorf("unsupported ALTER TABLE specification")
+}
+
+// Parses the "COMMENT [=] 'comment'" table option into the comment of the table
+func tableComment(p *parser, t *schema.Table) error {
+	if err := p.expectKeyword("COMMENT"); err != nil {
+		return err
+	}
+	p.symbol("=")
+	comment := p.next()
+	if comment.kind != STRING_TOKEN {
+		return p.errorf("expected table comment")
+	}
+	t.Comment = stringValue(comment)
+	return nil
+}
+
+func (s *State) renameIndex(t *schema.Table, oldName, newName string) error {
+	if i := slices.Index(s.fkIndexes[t.Name], oldName); i != -1 {
+		s.fkIndexes[t.Name][i] = newName
+		return nil
+	}
+	if index := t.GetIndex(oldName); index != nil {
+		index.Name = newName
+		return nil
+	}
+	if cols, exists := t.IndexToUniqueCols[oldName]; exists {
+		delete(t.IndexToUniqueCols, oldName)
+		t.IndexToUniqueCols[newName] = cols
+		return nil
+	}
+	return fmt.Errorf("index %s does not exist on %s", oldName, t.Name)
+}
@@ -0,0 +1,154 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	EOF_TOKEN          tokenKind = iota
+	IDENT_TOKEN                  // Keywords and unquoted identifiers
+	QUOTED_IDENT_TOKEN           // `identifier`
+	STRING_TOKEN                 // 'string' or "string"
+	NUMBER_TOKEN
+	SYMBOL_TOKEN // Single character punctuation. e.g. '(', ',', ';'
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // Unquoted value of the token
+	start int    // Position of the token in the source, quotes included
+	end   int
+}
+
+// Splits the given MySQL script into tokens. Comments and white spaces are dropped
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '#' || strings.HasPrefix(src[i:], "-- ") || strings.HasPrefix(src[i:], "--\t") ||
+			strings.HasPrefix(src[i:], "--\n") || src[i:] == "--":
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated comment at %d", i)
+			}
+			i += end + 4
+
+		case c == '`':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == '`' {
+					if j+1 < len(src) && src[j+1] == '`' { // Escaped backtick
+						sb.WriteByte('`')
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated identifier at %d", i)
+			}
+			tokens = append(tokens, token{kind: QUOTED_IDENT_TOKEN, text: sb.String(), start: i, end: j + 1})
+			i = j + 1
+
+		case c == '\'' || c == '"':
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == '\\' {
+					j++
+					continue
+				}
+				if src[j] == c {
+					if j+1 < len(src) && src[j+1] == c { // Escaped quote
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: STRING_TOKEN, text: src[i+1 : j], start: i, end: j + 1})
+			i = j + 1
+
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: NUMBER_TOKEN, text: src[i:j], start: i, end: j})
+			i = j
+
+		case isIdentChar(c):
+			j := i
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: IDENT_TOKEN, text: src[i:j], start: i, end: j})
+			i = j
+
+		default:
+			tokens = append(tokens, token{kind: SYMBOL_TOKEN, text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// Splits the tokens into statements by ';'. Empty statements are dropped
+func splitStatements(tokens []token) [][]token {
+	var statements [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.kind == SYMBOL_TOKEN && t.text == ";" {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+// Splits the given MySQL script into statements by ';', ignoring the ';' in strings, identifiers and comments.
+// Comments between statements and empty statements are dropped
+func SplitStatements(script string) ([]string, error) {
+	tokens, err := tokenize(script)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, statement := range splitStatements(tokens) {
+		statements = append(statements, script[statement[0].start:statement[len(statement)-1].end])
+	}
+	return statements, nil
+}
@@ -0,0 +1,133 @@
+package ddl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"single without semicolon", "DROP TABLE users", []string{"DROP TABLE users"}},
+		{"multiple", "CREATE TABLE a (id int);\nDROP TABLE b;\n", []string{"CREATE TABLE a (id int)", "DROP TABLE b"}},
+		{"empty statements", ";; DROP TABLE a ;\n;", []string{"DROP TABLE a"}},
+		{"semicolon in string", "INSERT INTO a VALUES ('x;y'); DROP TABLE a;", []string{"INSERT INTO a VALUES ('x;y')", "DROP TABLE a"}},
+		{"semicolon in double quoted string", `INSERT INTO a VALUES ("x;y");`, []string{`INSERT INTO a VALUES ("x;y")`}},
+		{"doubled quote", "INSERT INTO a VALUES ('it''s;');", []string{"INSERT INTO a VALUES ('it''s;')"}},
+		{"backslash escaped quote", `INSERT INTO a VALUES ('a\';b');`, []string{`INSERT INTO a VALUES ('a\';b')`}},
+		{"semicolon in identifier", "CREATE TABLE `a;b` (`c``;` int);", []string{"CREATE TABLE `a;b` (`c``;` int)"}},
+		{"line comments", "-- drop;\nDROP TABLE a; # b;\n", []string{"DROP TABLE a"}},
+		{"block comment", "/* a; b; */ DROP TABLE a; /* c; */", []string{"DROP TABLE a"}},
+		{"comment inside statement", "ALTER TABLE a /* ; */\n\tDROP COLUMN b; -- ;", []string{"ALTER TABLE a /* ; */\n\tDROP COLUMN b"}},
+		{"double dash without space", "SELECT 1--1;", []string{"SELECT 1--1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SplitStatements(test.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("SplitStatements(%q) = %q, want %q", test.script, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSplitStatementsErrors(t *testing.T) {
+	for _, script := range []string{
+		"INSERT INTO a VALUES ('x;",
+		"CREATE TABLE `a (id int);",
+		"DROP TABLE a; /* b;",
+	} {
+		if _, err := SplitStatements(script); err == nil {
+			t.Errorf("SplitStatements(%q) is split without an error", script)
+		}
+	}
+}
+
+func TestSplitPostgresStatements(t *testing.T) {
+	function := `CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+	NEW.updated_at = now();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql`
+	tagged := "CREATE FUNCTION f() RETURNS text AS $fn$ SELECT '$$;'; $fn$ LANGUAGE sql"
+	atomic := "CREATE FUNCTION add(a int, b int) RETURNS int LANGUAGE sql BEGIN ATOMIC SELECT CASE WHEN a > 0 THEN a END; SELECT a + b; END"
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"dollar quoted body", function + ";\nDROP TABLE a;", []string{function, "DROP TABLE a"}},
+		{"tagged dollar quote", tagged + ";", []string{tagged}},
+		{"parameters are not dollar quotes", "SELECT $1, $2; SELECT 1;", []string{"SELECT $1, $2", "SELECT 1"}},
+		{"dollar in identifier", "SELECT a$b$ FROM t; SELECT 1;", []string{"SELECT a$b$ FROM t", "SELECT 1"}},
+		{"backslash is not an escape", `INSERT INTO a VALUES ('C:\'); DROP TABLE a;`, []string{`INSERT INTO a VALUES ('C:\')`, "DROP TABLE a"}},
+		{"escape string", `INSERT INTO a VALUES (E'it\'s;'); DROP TABLE a;`, []string{`INSERT INTO a VALUES (E'it\'s;')`, "DROP TABLE a"}},
+		{"hash is not a comment", "INSERT INTO a VALUES ('#'); SELECT 2 # 3;", []string{"INSERT INTO a VALUES ('#')", "SELECT 2 # 3"}},
+		{"quoted identifier", `CREATE TABLE "a;b" ("c"";" int);`, []string{`CREATE TABLE "a;b" ("c"";" int)`}},
+		{"comments", "-- a;\nDROP TABLE a; --b;\n/* c; /* d; */ e; */", []string{"DROP TABLE a"}},
+		{"atomic body", atomic + ";\nSELECT 1;", []string{atomic, "SELECT 1"}},
+		{"column named function", "CREATE TABLE a (x int, y text, z int, function int); SELECT 1;", []string{"CREATE TABLE a (x int, y text, z int, function int)", "SELECT 1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SplitPostgresStatements(test.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("SplitPostgresStatements(%q) = %q, want %q", test.script, got, test.want)
+			}
+		})
+	}
+
+	for _, script := range []string{"SELECT $$a;", "SELECT 'a;", "/* a /* b */ SELECT 1;"} {
+		if _, err := SplitPostgresStatements(script); err == nil {
+			t.Errorf("SplitPostgresStatements(%q) is split without an error", script)
+		}
+	}
+}
+
+func TestSplitSQLiteStatements(t *testing.T) {
+	trigger := `CREATE TRIGGER touch AFTER UPDATE ON a
+BEGIN
+	UPDATE a SET updated = CASE WHEN NEW.x > 0 THEN 1 ELSE 0 END WHERE id = NEW.id;
+	DELETE FROM b WHERE a_id = NEW.id;
+END`
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"trigger body", trigger + ";\nDROP TABLE b;", []string{trigger, "DROP TABLE b"}},
+		{"backslash is not an escape", `INSERT INTO a VALUES ('C:\'); DROP TABLE a;`, []string{`INSERT INTO a VALUES ('C:\')`, "DROP TABLE a"}},
+		{"hash is not a comment", "INSERT INTO a VALUES ('#;'); DROP TABLE a;", []string{"INSERT INTO a VALUES ('#;')", "DROP TABLE a"}},
+		{"doubled quote", "INSERT INTO a VALUES ('it''s;');", []string{"INSERT INTO a VALUES ('it''s;')"}},
+		{"quoted identifiers", "CREATE TABLE [a;b] (`c;` int, \"d;\" int);", []string{"CREATE TABLE [a;b] (`c;` int, \"d;\" int)"}},
+		{"comments", "--a;\nPRAGMA foreign_keys = OFF; /* b; */\n-- c;", []string{"PRAGMA foreign_keys = OFF"}},
+		{"dollar is not a quote", "SELECT $a; SELECT 1;", []string{"SELECT $a", "SELECT 1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SplitSQLiteStatements(test.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("SplitSQLiteStatements(%q) = %q, want %q", test.script, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package migrator
+
+import (
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+)
+
+// Sorts the given tables so that referenced tables come before the tables that reference them.
+// Tables outside of the given list are considered to exist already. Self references are allowed.
+// If the references form a cycle, the references that close the cycle are returned as deferred,
+// they must be added after all of the tables are created
+func sortTablesByDependency(tables []*schema.Table) (sorted []*schema.Table, deferred []schema.Reference) {
+	remaining := slices.Clone(tables)
+
+	// Returns true if the reference points to another table that is not sorted yet
+	isPending := func(r schema.Reference) bool {
+		return r.ReferencedTableName != r.TableName && !slices.Contains(deferred, r) &&
+			slices.ContainsFunc(remaining, func(t *schema.Table) bool { return t.Name == r.ReferencedTableName })
+	}
+
+	for len(remaining) > 0 {
+		i := slices.IndexFunc(remaining, func(t *schema.Table) bool {
+			return !slices.ContainsFunc(t.References, isPending)
+		})
+		if i == -1 {
+			// Every remaining table depends on another one, so there is a cycle. Defer the reference that closes it
+			deferred = append(deferred, findCycleReference(remaining, isPending))
+			continue
+		}
+
+		sorted = append(sorted, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+
+	return sorted, deferred
+}
+
+// Follows the pending references starting from the first table until a table is visited twice.
+// Returns the reference that leads back to the visited table
+func findCycleReference(tables []*schema.Table, isPending func(schema.Reference) bool) schema.Reference {
+	var visited []string
+	t := tables[0]
+	for {
+		visited = append(visited, t.Name)
+		r := t.References[slices.IndexFunc(t.References, isPending)]
+		if slices.Contains(visited, r.ReferencedTableName) {
+			return r
+		}
+		t = tables[slices.IndexFunc(tables, func(t *schema.Table) bool { return t.Name == r.ReferencedTableName })]
+	}
+}
+
+// Returns a copy of the table without the given references
+func withoutReferences(t *schema.Table, references []schema.Reference) *schema.Table {
+	table := *t
+	table.References = slices.DeleteFunc(slices.Clone(t.References), func(r schema.Reference) bool {
+		return slices.Contains(references, r)
+	})
+	return &table
+}
@@ -0,0 +1,62 @@
+package migrator
+
+import (
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+	"testing"
+)
+
+// Returns a table with a reference to each of the given tables
+func referencingTable(name string, referenced ...string) *schema.Table {
+	t := &schema.Table{Name: name}
+	for _, r := range referenced {
+		t.References = append(t.References, schema.Reference{TableName: name, ColumnName: r + "_id", ReferencedTableName: r, ReferencedColumnName: "id"})
+	}
+	return t
+}
+
+func TestSortTablesByDependency(t *testing.T) {
+	tests := []struct {
+		name     string
+		tables   []*schema.Table
+		sorted   []string
+		deferred []string // Tables of the deferred references, followed by the referenced table. e.g. "a->b"
+	}{
+		{"no references", []*schema.Table{referencingTable("a"), referencingTable("b")}, []string{"a", "b"}, nil},
+		{"referenced table first", []*schema.Table{referencingTable("posts", "users"), referencingTable("users")}, []string{"users", "posts"}, nil},
+		{"chain", []*schema.Table{referencingTable("c", "b"), referencingTable("b", "a"), referencingTable("a")}, []string{"a", "b", "c"}, nil},
+		{"self reference", []*schema.Table{referencingTable("employees", "employees")}, []string{"employees"}, nil},
+		{"table outside of the list", []*schema.Table{referencingTable("posts", "users")}, []string{"posts"}, nil},
+		{"two table cycle", []*schema.Table{referencingTable("a", "b"), referencingTable("b", "a")}, []string{"b", "a"}, []string{"b->a"}},
+		{"three table cycle", []*schema.Table{referencingTable("a", "b"), referencingTable("b", "c"), referencingTable("c", "a")},
+			[]string{"c", "b", "a"}, []string{"c->a"}},
+		{"cycle with a dependent table", []*schema.Table{referencingTable("d", "a"), referencingTable("a", "b"), referencingTable("b", "a")},
+			[]string{"b", "a", "d"}, []string{"b->a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorted, deferred := sortTablesByDependency(test.tables)
+			if got := tableNames(sorted); !slices.Equal(got, test.sorted) {
+				t.Errorf("sorted tables %v, want %v", got, test.sorted)
+			}
+			var got []string
+			for _, r := range deferred {
+				got = append(got, r.TableName+"->"+r.ReferencedTableName)
+			}
+			if !slices.Equal(got, test.deferred) {
+				t.Errorf("deferred references %v, want %v", got, test.deferred)
+			}
+
+			// Every table is created after the tables it references, except by the deferred references
+			for i, table := range sorted {
+				for _, r := range table.References {
+					j := slices.IndexFunc(sorted, func(t *schema.Table) bool { return t.Name == r.ReferencedTableName })
+					if j > i && !slices.Contains(deferred, r) {
+						t.Errorf("%s is created before %s it references", table.Name, r.ReferencedTableName)
+					}
+				}
+			}
+		})
+	}
+}
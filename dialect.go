@@ -0,0 +1,189 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"github.com/AkifSahn/migrator/schema"
+	"strings"
+)
+
+// Dialect owns everything that differs between database engines:
+// reading the current schema, mapping go types into column types and building the migration queries
+type Dialect interface {
+	Introspector
+	QueryBuilder
+
+	// Returns the name of the dialect. e.g. "mysql"
+	Name() string
+
+	// Returns the database/sql driver name used to open connections for this dialect
+	DriverName() string
+
+	// Converts the given go type into a column type of the dialect
+	DataType(goType string) (string, error)
+
+	// Returns the canonical form of the given column type, so the types of the models and the database compare equal.
+	// Dialect is the 'schema.TypeRules' of the migrations. e.g. "int(11)" and "integer" are "int" for MySQL
+	CanonicalType(t schema.ColumnType) schema.ColumnType
+
+	// Returns the query that creates the golang-migrate compatible 'schema_migrations' table if it doesn't exist
+	CreateVersionTableQuery() string
+
+	// Returns the query that inserts a row into the 'schema_migrations' table, the version and the dirty flag are its arguments
+	InsertVersionQuery() string
+
+	// Splits a migration script into the statements it is run by, following the quoting and comment rules of the dialect.
+	// Statements are returned without the ';' at their end
+	SplitStatements(script string) ([]string, error)
+}
+
+// Introspector reads the current state of a database schema
+type Introspector interface {
+	// Returns the names of every table in the schema
+	TableNames(ctx context.Context, db *sql.DB, schemaName string) ([]string, error)
+
+	// Returns the 'map[tableName] -> comment' of the tables of the schema that have a comment
+	TableComments(ctx context.Context, db *sql.DB, schemaName string) (map[string]string, error)
+
+	// Returns the columns of the given table in their ordinal order
+	DescribeTable(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Column, error)
+
+	// Returns the primary key columns of the given table in their key order
+	GetPrimaryKey(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]string, error)
+
+	// Returns the foreign keys declared on the given table
+	GetReferences(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Reference, error)
+
+	// Returns the 'map[indexName] -> []ColumnName' of the unique constraints of the given table
+	GetUniqueIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) (map[string][]string, error)
+
+	// Returns the indexes of the given table, except the primary key, unique constraints and foreign key indexes
+	GetIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error)
+
+	// Returns the check constraints of the given table
+	GetChecks(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Check, error)
+}
+
+// QueryBuilder creates the migration queries.
+// Every query is terminated by ';' and a new line, so they can be concatenated into a script
+type QueryBuilder interface {
+	CreateTableQuery(t *schema.Table) (string, error)
+	DropTableQuery(t *schema.Table) (string, error)
+	AddColumnQuery(t schema.Table, c schema.Column) (string, error)
+	DropColumnQuery(t schema.Table, c schema.Column) (string, error)
+	ModifyColumnQuery(t schema.Table, c schema.Column, old schema.Column) (string, error)
+	RenameColumnQuery(t schema.Table, newCol schema.Column, oldColumn schema.Column) (string, error)
+	RenameTableQuery(oldName, newName string) (string, error)
+	AddReferenceQuery(reference schema.Reference) (string, error)
+	DropReferenceQuery(reference schema.Reference) (string, error)
+	AddUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error)
+	DropUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error)
+	AddIndexQuery(table schema.Table, index schema.Index) (string, error)
+	// Primary key queries are given the key columns in their key order
+	AddPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error)
+	DropPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error)
+	DropIndexQuery(table schema.Table, index schema.Index) (string, error)
+	AddCheckQuery(table schema.Table, check schema.Check) (string, error)
+	DropCheckQuery(table schema.Table, check schema.Check) (string, error)
+}
+
+// TableRebuilder is implemented by dialects that cannot apply some migrations in place.
+// Such tables are migrated by creating the new version of the table and copying the rows into it
+type TableRebuilder interface {
+	// Returns true if the given migration cannot be applied by an ALTER query
+	RequiresRebuild(migration *schema.ColumnMigration) bool
+
+	// Returns the query that turns the 'from' table into the 'to' table.
+	// 'renamed' maps old column names to new column names so their values are copied
+	RebuildTableQuery(from *schema.Table, to *schema.Table, renamed map[string]string) (string, error)
+}
+
+// PrimaryKeyNamer is implemented by dialects that drop the primary key by the name of its constraint
+type PrimaryKeyNamer interface {
+	// Returns the name of the primary key constraint of the given table, empty if the table has no primary key
+	GetPrimaryKeyName(ctx context.Context, db *sql.DB, schemaName, tableName string) (string, error)
+}
+
+// Returns the 'map[tableName] -> comment' of the given query that selects the table names and comments
+func queryTableComments(ctx context.Context, db *sql.DB, query string, args ...interface{}) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	comments := make(map[string]string)
+	for rows.Next() {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return nil, &IntrospectionError{Query: query, Err: err}
+		}
+		comments[name] = comment
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+	return comments, nil
+}
+
+// Returns the primary key columns of the table selected by the given query in their key order
+func queryPrimaryKey(ctx context.Context, db *sql.DB, tableName, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+		cols = append(cols, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	return cols, nil
+}
+
+// Returns the check constraints selected by the given query, which selects the constraint names and expressions
+func queryChecks(ctx context.Context, db *sql.DB, tableName, query string, args ...interface{}) ([]schema.Check, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var checks []schema.Check
+	for rows.Next() {
+		var check schema.Check
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+		checks = append(checks, check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	return checks, nil
+}
+
+// Returns the value as a single quoted SQL string literal, quotes in the value are doubled
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Returns the default value of an introspected column in the canonical form of 'schema.NormalizeDefault'.
+// 'literal' is true if the database reports literals without their quotes. Values that can't be normalized are kept as they are
+func introspectedDefault(columnType string, value sql.NullString, literal bool) sql.NullString {
+	if !value.Valid {
+		return value
+	}
+	normalized, err := schema.NormalizeDefault(columnType, value.String, literal)
+	if err != nil {
+		return value
+	}
+	return normalized
+}
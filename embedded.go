@@ -0,0 +1,85 @@
+package migrator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// A field of a model after the embedded structs are flattened
+type modelField struct {
+	reflect.StructField
+	Path   string // Go path of the field in the model. e.g. "Author.Name"
+	Prefix string // Prefix of the column name from the 'embeddedPrefix' tags
+}
+
+// Returns the fields of the given struct type that are parsed into columns.
+// Anonymous structs and fields with the 'embedded' or 'embeddedPrefix' tag are flattened into the fields of the model,
+// 'embeddedPrefix' is prepended to the names of the embedded columns as it is. e.g. "author_".
+// Fields with the '-' tag and 'RenamedFrom' markers are skipped
+func (m *Migrator) modelFields(typ reflect.Type, prefix, path string) []modelField {
+	var fields []modelField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldPath := path + field.Name
+		if isIgnored(field.Tag.Get("gorm")) || field.Type == renamedFromType {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		embedded, embeddedPrefix := parseEmbeddedTag(field.Tag.Get("gorm"))
+		if fieldType.Kind() == reflect.Struct && (field.Anonymous || embedded) && !m.isDataType(fieldType) {
+			embeddedFields := m.modelFields(fieldType, prefix+embeddedPrefix, fieldPath+".")
+			if isGormModel(fieldType) {
+				setGormModelTags(embeddedFields)
+			}
+			fields = append(fields, embeddedFields...)
+			continue
+		}
+
+		fields = append(fields, modelField{StructField: field, Path: fieldPath, Prefix: prefix})
+	}
+	return fields
+}
+
+// Returns true if the gorm tag excludes the field from migrations. e.g. "-", "-:all" or "-:migration"
+func isIgnored(tag string) bool {
+	for _, setting := range parseTagSettings(tag) {
+		if value := strings.ToLower(strings.TrimSpace(setting.Value)); setting.Key == "-" && (value == "" || value == "all" || value == "migration") {
+			return true
+		}
+	}
+	return false
+}
+
+// Parses the 'embedded' and 'embeddedPrefix' settings of the given gorm tag
+func parseEmbeddedTag(tag string) (embedded bool, prefix string) {
+	for _, setting := range parseTagSettings(tag) {
+		switch setting.Key {
+		case "EMBEDDED":
+			embedded = true
+		case "EMBEDDEDPREFIX":
+			embedded = true
+			prefix = setting.Value
+		}
+	}
+	return embedded, prefix
+}
+
+// Returns true if the type is gorm.Model
+func isGormModel(typ reflect.Type) bool {
+	return typ.PkgPath() == "gorm.io/gorm" && typ.Name() == "Model"
+}
+
+// gorm.Model declares its ID as "primarykey" and relies on gorm making integer primary keys auto increment.
+// Tags are replaced so the columns are the same as the ones gorm creates
+func setGormModelTags(fields []modelField) {
+	for i := range fields {
+		if strings.HasSuffix(fields[i].Path, ".ID") {
+			fields[i].Tag = `gorm:"primaryKey;auto_increment"`
+		}
+	}
+}
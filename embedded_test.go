@@ -0,0 +1,75 @@
+package migrator
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type auditFields struct {
+	CreatedAt time.Time
+	CreatedBy string
+}
+
+type address struct {
+	City    string
+	Country string
+	Geo     geoPoint `gorm:"embeddedPrefix:geo_"`
+}
+
+type geoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+type embeddingModel struct {
+	ID uint `gorm:"primaryKey"`
+	auditFields
+	Home    address  `gorm:"embeddedPrefix:home_"`
+	Work    *address `gorm:"embedded"`
+	Ignored string   `gorm:"-"`
+}
+
+func TestModelFields(t *testing.T) {
+	m := &Migrator{Dialect: MySQLDialect{}}
+	want := []struct {
+		path, prefix string
+	}{
+		{"ID", ""},
+		{"auditFields.CreatedAt", ""},
+		{"auditFields.CreatedBy", ""},
+		{"Home.City", "home_"},
+		{"Home.Country", "home_"},
+		{"Home.Geo.Lat", "home_geo_"},
+		{"Home.Geo.Lng", "home_geo_"},
+		{"Work.City", ""},
+		{"Work.Country", ""},
+		{"Work.Geo.Lat", "geo_"},
+		{"Work.Geo.Lng", "geo_"},
+	}
+
+	fields := m.modelFields(reflect.TypeOf(embeddingModel{}), "", "")
+	if len(fields) != len(want) {
+		var paths []string
+		for _, f := range fields {
+			paths = append(paths, f.Path)
+		}
+		t.Fatalf("fields %v, want %d fields", paths, len(want))
+	}
+	for i, f := range fields {
+		if f.Path != want[i].path || f.Prefix != want[i].prefix {
+			t.Errorf("field %d is %s with prefix %q, want %s with prefix %q", i, f.Path, f.Prefix, want[i].path, want[i].prefix)
+		}
+	}
+
+	tables, err := m.ParseTablesFromStructs(embeddingModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := []string{"id", "created_at", "created_by", "home_city", "home_country", "home_geo_lat", "home_geo_lng",
+		"city", "country", "geo_lat", "geo_lng"}
+	if got := columnNames(tables[0]); !slices.Equal(got, columns) {
+		t.Errorf("columns %v, want %v", got, columns)
+	}
+}
@@ -0,0 +1,147 @@
+package migrator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagParseError is returned when a struct field or its gorm tag cannot be parsed into a column
+type TagParseError struct {
+	Model string
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *TagParseError) Error() string {
+	if e.Tag != "" {
+		return fmt.Sprintf("cannot parse field %s.%s with tag %q: %v", e.Model, e.Field, e.Tag, e.Err)
+	}
+	return fmt.Sprintf("cannot parse field %s.%s: %v", e.Model, e.Field, e.Err)
+}
+
+func (e *TagParseError) Unwrap() error {
+	return e.Err
+}
+
+// ModelError is returned when a model as a whole cannot be turned into a table
+type ModelError struct {
+	Model  string
+	Reason string
+}
+
+func (e *ModelError) Error() string {
+	return fmt.Sprintf("invalid model %s: %s", e.Model, e.Reason)
+}
+
+// IntrospectionError is returned when the current state of the database cannot be read
+type IntrospectionError struct {
+	Table string // Empty if the failing query is not about a single table
+	Query string
+	Err   error
+}
+
+func (e *IntrospectionError) Error() string {
+	if e.Table != "" {
+		return fmt.Sprintf("cannot introspect table %s: %v", e.Table, e.Err)
+	}
+	return fmt.Sprintf("cannot introspect database: %v", e.Err)
+}
+
+func (e *IntrospectionError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedOperationError is returned when a migration operation cannot be expressed as a query
+type UnsupportedOperationError struct {
+	Operation string
+	Table     string
+	Column    string // Empty if the operation is not about a single column
+	Reason    string
+}
+
+func (e *UnsupportedOperationError) Error() string {
+	target := e.Table
+	if e.Column != "" {
+		target = fmt.Sprintf("%s.%s", e.Table, e.Column)
+	}
+	return fmt.Sprintf("unsupported operation %s on %s: %s", e.Operation, target, e.Reason)
+}
+
+// DirtyError is returned when the last migration failed half way. The database must be fixed by hand and
+// the version set by 'Migrator.Force' before running migrations again
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("database is dirty at version %d, fix it and force a version", e.Version)
+}
+
+// VersionError is returned when the migration files of a directory have duplicate or missing versions
+type VersionError struct {
+	Dir        string
+	Duplicates []int64    // Versions used by more than one migration
+	Gaps       [][2]int64 // Ranges of missing versions between sequential migrations, both ends included
+}
+
+func (e *VersionError) Error() string {
+	var problems []string
+	for _, v := range e.Duplicates {
+		problems = append(problems, fmt.Sprintf("version %d is used by more than one migration", v))
+	}
+	for _, gap := range e.Gaps {
+		if gap[0] == gap[1] {
+			problems = append(problems, fmt.Sprintf("version %d is missing", gap[0]))
+		} else {
+			problems = append(problems, fmt.Sprintf("versions %d to %d are missing", gap[0], gap[1]))
+		}
+	}
+	return fmt.Sprintf("inconsistent migration versions in %s: %s", e.Dir, strings.Join(problems, ", "))
+}
+
+// DestructiveChangeError is returned when the migration would drop or narrow data that is not allowed to be lost
+type DestructiveChangeError struct {
+	Changes []DestructiveChange
+}
+
+func (e *DestructiveChangeError) Error() string {
+	changes := make([]string, 0, len(e.Changes))
+	for _, c := range e.Changes {
+		changes = append(changes, c.String())
+	}
+	return fmt.Sprintf("migration has destructive changes, allow them by 'AllowDrops', an %q annotation in %s or 'AllowDestructive': %s",
+		ALLOW_DROP_ANNOTATION, ALLOW_DROP_FILE, strings.Join(changes, ", "))
+}
+
+// RenameError is returned when a dropped and an added column look like a rename,
+// but there is no way to ask the user to confirm it
+type RenameError struct {
+	Table string
+	From  string
+	To    string
+}
+
+func (e *RenameError) Error() string {
+	return fmt.Sprintf("column %s.%s looks renamed to %s, add `migrator:\"renamedFrom:%s\"` tag to the field to rename it",
+		e.Table, e.From, e.To, e.From)
+}
+
+// VerifyError is returned when a migration fails on the shadow database, or the shadow schema is not the expected one after it
+type VerifyError struct {
+	Step      string // e.g. "up script", "migration 3_add_users"
+	Statement string // Statement that failed. Empty if the schema is different
+	Diff      string // Statements that are still required to reach the expected schema. Empty if a statement failed
+	Err       error
+}
+
+func (e *VerifyError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("verifying %s on the shadow database: %v in statement: %s", e.Step, e.Err, e.Statement)
+	}
+	return fmt.Sprintf("verifying %s on the shadow database: schema is different, remaining migration:\n%s", e.Step, e.Diff)
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Matches Flyway versioned and undo migrations. e.g. "V3__add_users.sql" and "U3__add_users.sql"
+var flywayFileRegexp = regexp.MustCompile(`^([VU])(\d+)__(.*)\.sql$`)
+
+// FlywayWriter saves migrations as "V<version>__<name>.sql" and "U<version>__<name>.sql" files,
+// the versioned and undo migrations of Flyway
+type FlywayWriter struct{}
+
+var _ MigrationWriter = FlywayWriter{}
+
+func (FlywayWriter) ReadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migration directory: %w", err)
+	}
+
+	var migrations []Migration
+	undo := make(map[int64]string) // version -> undo script
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := flywayFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing version of %s: %w", entry.Name(), err)
+		}
+		content, err := readFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if match[1] == "U" {
+			undo[version] = content
+			continue
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[3], Up: content})
+	}
+
+	for i := range migrations {
+		migrations[i].Down = undo[migrations[i].Version]
+	}
+	for version := range undo {
+		if !slices.ContainsFunc(migrations, func(m Migration) bool { return m.Version == version }) {
+			return nil, fmt.Errorf("undo migration %d has no versioned migration", version)
+		}
+	}
+
+	return sortMigrations(dir, migrations)
+}
+
+func (FlywayWriter) WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error) {
+	name := fmt.Sprintf("%d__%s.sql", migration.Version, migration.Name)
+	return writeFiles([]outputFile{
+		{Path: filepath.Join(dir, "V"+name), Content: migration.Up},
+		{Path: filepath.Join(dir, "U"+name), Content: migration.Down},
+	}, overwrite)
+}
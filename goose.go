@@ -0,0 +1,24 @@
+package migrator
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// GooseWriter saves migrations as a single "<version>_<name>.sql" file
+// with "-- +goose Up" and "-- +goose Down" annotations, the format of github.com/pressly/goose
+type GooseWriter struct{}
+
+var _ MigrationWriter = GooseWriter{}
+
+func (GooseWriter) ReadMigrations(dir string) ([]Migration, error) {
+	return readSingleFileMigrations(dir, func(path, content string) (string, string, error) {
+		return splitAnnotated(path, content, "-- +goose Up", "-- +goose Down")
+	})
+}
+
+func (GooseWriter) WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error) {
+	content := fmt.Sprintf("-- +goose Up\n%s\n-- +goose Down\n%s", migration.Up, migration.Down)
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s.sql", migration.Version, migration.Name))
+	return writeFiles([]outputFile{{Path: path, Content: content}}, overwrite)
+}
@@ -0,0 +1,217 @@
+package migrator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Annotation that allows a destructive change of the next migration, written in 'ALLOW_DROP_FILE'.
+// e.g. "-- migrator:allow-drop users" or "-- migrator:allow-drop users.email"
+const ALLOW_DROP_ANNOTATION = "-- migrator:allow-drop"
+
+// File of the migrations directory that holds the allow-drop annotations of the next migration.
+// It is removed once the migration is saved, so its annotations don't allow the drops of later migrations
+const ALLOW_DROP_FILE = "allow_drop.txt"
+
+// DestructiveChange is a migration operation that may lose data
+type DestructiveChange struct {
+	Operation string // e.g. "DROP TABLE", "DROP COLUMN"
+	Table     string
+	Column    string // Empty if the whole table is affected
+	Reason    string
+}
+
+func (c DestructiveChange) String() string {
+	target := c.Table
+	if c.Column != "" {
+		target = fmt.Sprintf("%s.%s", c.Table, c.Column)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Operation, target, c.Reason)
+}
+
+// Returns the destructive changes of the up migration that are not allowed.
+// Changes are allowed by 'Migrator.AllowDestructive', or one by one by 'Migrator.AllowDrops' and the annotations
+// of the allow-drop file of the given directory. e.g. "users" allows dropping the users table,
+// "users.email" allows the changes of its email column
+func (m *Migrator) checkDestructiveChanges(dir string, deletedTables []*schema.Table, alteredTables []*schema.TablePair) error {
+	if m.AllowDestructive {
+		return nil
+	}
+
+	var changes []DestructiveChange
+	for _, t := range deletedTables {
+		changes = append(changes, DestructiveChange{Operation: "DROP TABLE", Table: t.Name, Reason: "table has no model"})
+	}
+	for _, pair := range alteredTables {
+		for _, migration := range pair.First.CompareWith(pair.Second, m.Dialect) {
+			if change, ok := destructiveChange(pair.First.Name, migration, m.Dialect); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	annotated, err := allowedDrops(dir)
+	if err != nil {
+		return err
+	}
+	allowed := append(slices.Clone(m.AllowDrops), annotated...)
+	changes = slices.DeleteFunc(changes, func(c DestructiveChange) bool {
+		target := c.Table
+		if c.Column != "" {
+			target = fmt.Sprintf("%s.%s", c.Table, c.Column)
+		}
+		return slices.Contains(allowed, target)
+	})
+	if len(changes) == 0 {
+		return nil
+	}
+	return &DestructiveChangeError{Changes: changes}
+}
+
+// Returns the destructive change of the given column migration of the table, if it is one.
+// Column types are compared by the given rules
+func destructiveChange(table string, migration *schema.ColumnMigration, rules schema.TypeRules) (DestructiveChange, bool) {
+	switch migration.Operation {
+	case schema.DROP_COLUMN:
+		column := migration.ApplyOn.(schema.Column)
+		return DestructiveChange{Operation: "DROP COLUMN", Table: table, Column: column.Name, Reason: "column has no field"}, true
+
+	case schema.ADD_COLUMN:
+		column := migration.ApplyOn.(schema.Column)
+		if requiresValue(column) {
+			return DestructiveChange{Operation: "ADD COLUMN", Table: table, Column: column.Name, Reason: "NOT NULL column has no default value for the existing rows"}, true
+		}
+
+	case schema.MODIFY_COLUMN:
+		column := migration.ApplyOn.(schema.Column)
+		old := migration.Old.(schema.Column)
+		if isNarrowing(schema.CanonicalColumnType(old.ColumnType, rules), schema.CanonicalColumnType(column.ColumnType, rules)) {
+			return DestructiveChange{Operation: "MODIFY COLUMN", Table: table, Column: column.Name,
+				Reason: fmt.Sprintf("%s cannot hold every %s value", column.ColumnType, old.ColumnType)}, true
+		}
+		if old.Null != "NO" && requiresValue(column) {
+			return DestructiveChange{Operation: "MODIFY COLUMN", Table: table, Column: column.Name, Reason: "NULL values cannot be kept in a NOT NULL column without a default value"}, true
+		}
+	}
+	return DestructiveChange{}, false
+}
+
+// Returns true if every row must be given a value for the column, which existing rows don't have
+func requiresValue(c schema.Column) bool {
+	return c.Null == "NO" && !c.DefaultValue.Valid && !c.PrimaryKey && !strings.Contains(strings.ToLower(c.Extra), "auto_increment")
+}
+
+// Sizes of the text types, types without a size are unlimited
+var textSizes = map[string]int64{
+	"tinytext":   1<<8 - 1,
+	"text":       1<<16 - 1,
+	"mediumtext": 1<<24 - 1,
+	"longtext":   1<<32 - 1,
+}
+
+// Integer types from the smallest to the largest
+var integerTypes = [][]string{
+	{"tinyint"},
+	{"smallint", "int2"},
+	{"mediumint"},
+	{"int", "integer", "int4"},
+	{"bigint", "int8"},
+}
+
+// Returns true if the new column type may not hold every value of the old column type
+func isNarrowing(oldType, newType schema.ColumnType) bool {
+	if oldType.Equals(newType) {
+		return false
+	}
+
+	if oldRank, newRank := integerRank(oldType.Base), integerRank(newType.Base); oldRank != -1 && newRank != -1 {
+		// Unsigned values only fit into a larger signed type
+		if oldType.Unsigned && !newType.Unsigned {
+			return newRank <= oldRank
+		}
+		return newRank < oldRank || newType.Unsigned != oldType.Unsigned
+	}
+	if oldType.Unsigned != newType.Unsigned {
+		return true
+	}
+	if oldSize, ok := textSize(oldType); ok {
+		newSize, ok := textSize(newType)
+		return !ok || (newSize != -1 && (oldSize == -1 || newSize < oldSize))
+	}
+	if oldType.Base == "float" && (newType.Base == "double" || newType.Base == "double precision") {
+		return false
+	}
+	if oldType.Base != newType.Base {
+		return true
+	}
+
+	// Enum and set values must all be kept
+	for _, arg := range oldType.Args {
+		if !slices.Contains(newType.Args, arg) {
+			return true
+		}
+	}
+
+	// Same type with a smaller length, precision or scale. e.g. decimal(10,2) -> decimal(8,2)
+	smaller := func(oldSize, newSize int) bool { return oldSize != -1 && (newSize == -1 || newSize < oldSize) }
+	return smaller(oldType.Length, newType.Length) || smaller(oldType.Precision, newType.Precision) || smaller(oldType.Scale, newType.Scale)
+}
+
+func integerRank(base string) int {
+	return slices.IndexFunc(integerTypes, func(names []string) bool { return slices.Contains(names, base) })
+}
+
+// Returns the number of characters the text type can hold, -1 if unlimited. False if it is not a text type
+func textSize(t schema.ColumnType) (int64, bool) {
+	switch t.Base {
+	case "char", "varchar", "character varying", "character":
+		return int64(t.Length), true
+	}
+	if size, ok := textSizes[t.Base]; ok {
+		return size, true
+	}
+	return 0, false
+}
+
+// Returns the tables and columns allowed by the annotations of the allow-drop file of the given directory.
+// Missing file means nothing is allowed
+func allowedDrops(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	file, err := os.Open(filepath.Join(dir, ALLOW_DROP_FILE))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var allowed []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if target, ok := strings.CutPrefix(line, ALLOW_DROP_ANNOTATION); ok {
+			allowed = append(allowed, strings.Fields(target)...)
+		}
+	}
+	return allowed, scanner.Err()
+}
+
+// Removes the allow-drop file of the given directory, its annotations are used by the saved migration
+func consumeAllowedDrops(dir string) error {
+	if err := os.Remove(filepath.Join(dir, ALLOW_DROP_FILE)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing %s: %w", ALLOW_DROP_FILE, err)
+	}
+	return nil
+}
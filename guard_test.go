@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type guardUser struct {
+	ID    int64 `gorm:"primaryKey"`
+	Email string
+	Name  string
+}
+
+func (guardUser) TableName() string { return "users" }
+
+type guardLog struct {
+	ID int64 `gorm:"primaryKey"`
+}
+
+// Same table without the email and name columns
+type guardUserWithoutColumns struct {
+	ID int64 `gorm:"primaryKey"`
+}
+
+func (guardUserWithoutColumns) TableName() string { return "users" }
+
+func TestAllowDropAnnotation(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewOfflineMigrator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.MigrateWithOptions(ctx, Options{Name: "init", Dir: dir, Output: io.Discard}, guardUser{}, guardLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		annotations string // Content of the allow-drop file, no file if empty
+		refused     []string
+	}{
+		{"not annotated", "", []string{"users.email", "users.name", "guard_logs"}},
+		{"partly annotated", "-- migrator:allow-drop users.email\n", []string{"users.name", "guard_logs"}},
+		{"annotated", "-- migrator:allow-drop users.email guard_logs\n  -- migrator:allow-drop users.name\n", nil},
+		{"not an annotation", "users.email users.name guard_logs\n-- users.email\n", []string{"users.email", "users.name", "guard_logs"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, ALLOW_DROP_FILE)
+			os.Remove(path)
+			if test.annotations != "" {
+				if err := os.WriteFile(path, []byte(test.annotations), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, _, err := m.createMigrationScripts(ctx, dir, guardUserWithoutColumns{})
+			var destructive *DestructiveChangeError
+			if test.refused == nil {
+				if err != nil {
+					t.Fatalf("annotated drops are refused: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &destructive) {
+				t.Fatalf("got error %v, want *DestructiveChangeError", err)
+			}
+			var refused []string
+			for _, c := range destructive.Changes {
+				target := c.Table
+				if c.Column != "" {
+					target += "." + c.Column
+				}
+				refused = append(refused, target)
+			}
+			slices.Sort(refused)
+			want := slices.Clone(test.refused)
+			slices.Sort(want)
+			if !slices.Equal(refused, want) {
+				t.Errorf("refused changes %v, want %v", refused, want)
+			}
+		})
+	}
+}
+
+func TestAllowDropFileIsConsumed(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewOfflineMigrator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.MigrateWithOptions(ctx, Options{Name: "init", Dir: dir, Output: io.Discard}, guardUser{}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, ALLOW_DROP_FILE)
+	if err := os.WriteFile(path, []byte("-- migrator:allow-drop users.email users.name\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.MigrateWithOptions(ctx, Options{Name: "drop columns", Dir: dir, Output: io.Discard}, guardUserWithoutColumns{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s is left after the migration is saved: %v", ALLOW_DROP_FILE, err)
+	}
+}
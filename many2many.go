@@ -0,0 +1,117 @@
+package migrator
+
+import (
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"github.com/AkifSahn/migrator/utils"
+	"reflect"
+	"slices"
+)
+
+// Join table of a many2many relation field. e.g. `Languages []Language gorm:"many2many:user_languages"`.
+// Join tables are created after every model is parsed, since they need the primary key of the related model
+type joinTable struct {
+	Name         string        // Name of the join table. e.g. "user_languages"
+	Table        *schema.Table // Table of the model that declares the relation
+	ModelName    string        // e.g. "User"
+	FieldName    string        // Name of the relation field. e.g. "Languages"
+	Related      reflect.Type  // Model type of the relation field. e.g. 'Language'
+	ForeignKey   string        // Field name of the join column that references the model, set by 'joinForeignKey'. e.g. "UserID"
+	Reference    string        // Field name of the join column that references the related model, set by 'joinReferences'. e.g. "LanguageID"
+	DeleteOption schema.ReferenceOption
+	UpdateOption schema.ReferenceOption
+}
+
+// Appends the join tables of the parsed many2many relations to the given tables.
+// Join tables declared by both models of the relation, or declared as a model themselves, are created once.
+// Related models must be given as well, models of unmanaged tables are only used for their primary key
+func (m *Migrator) appendJoinTables(tables []*schema.Table) ([]*schema.Table, error) {
+	for _, join := range m.joinTables {
+		if slices.ContainsFunc(tables, func(t *schema.Table) bool { return t.Name == join.Name }) {
+			continue
+		}
+
+		relatedName := m.tableName(join.Related)
+		i := slices.IndexFunc(tables, func(t *schema.Table) bool { return t.Name == relatedName })
+		if i == -1 {
+			return nil, &ModelError{Model: join.ModelName, Reason: fmt.Sprintf("many2many relation %s requires the %s model", join.FieldName, join.Related.Name())}
+		}
+		table, err := m.newJoinTable(join, tables[i])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// Returns the join table of the relation. Its primary key is made of the column referencing the model
+// and the column referencing the related model, both columns have a foreign key
+func (m *Migrator) newJoinTable(join joinTable, related *schema.Table) (*schema.Table, error) {
+	if len(join.Table.PrimaryCols) != 1 || len(related.PrimaryCols) != 1 {
+		return nil, &ModelError{Model: join.ModelName, Reason: fmt.Sprintf("many2many relation %s requires both models to have a single primary key column", join.FieldName)}
+	}
+	ownKey := join.Table.GetPrimaryKeyColumn()
+	relatedKey := related.GetPrimaryKeyColumn()
+
+	// Same default names as gorm. Self referencing relations are named after the field. e.g. "UserID" and "FriendID" for 'Friends []User'
+	relatedName := join.Related.Name()
+	ownField := join.ModelName + ownKey.FieldName
+	relatedField := relatedName + relatedKey.FieldName
+	if relatedField == ownField {
+		relatedField = utils.Singular(join.FieldName) + relatedKey.FieldName
+		if join.FieldName == relatedName {
+			relatedField = relatedName + relatedKey.FieldName + "Reference"
+		}
+	}
+	relatedRelation := relatedName
+	if relatedRelation == join.ModelName {
+		relatedRelation = join.FieldName
+	}
+	// Field with the name of its own model, the foreign keys are told apart by the column. e.g. 'Node []Node'
+	if relatedRelation == join.ModelName {
+		relatedRelation = relatedField
+	}
+	if join.ForeignKey != "" {
+		ownField = join.ForeignKey
+	}
+	if join.Reference != "" {
+		relatedField = join.Reference
+	}
+
+	table := &schema.Table{Name: join.Name, IndexToUniqueCols: make(map[string][]string)}
+	for _, key := range []struct {
+		Field    string
+		Relation string
+		Column   *schema.Column
+		Table    string
+	}{
+		{ownField, join.ModelName, ownKey, join.Table.Name},
+		{relatedField, relatedRelation, relatedKey, related.Name},
+	} {
+		col := &schema.Column{
+			TableName:  table.Name,
+			Name:       m.namer().ColumnName(table.Name, key.Field),
+			FieldName:  key.Field,
+			ColumnType: key.Column.ColumnType,
+			Null:       "NO",
+			PrimaryKey: true,
+			ForeignKey: true,
+		}
+		if table.GetColumn(col.Name) != nil {
+			return nil, &ModelError{Model: join.ModelName, Reason: fmt.Sprintf("join table %s has two %s columns, set joinForeignKey or joinReferences", table.Name, col.Name)}
+		}
+		table.Columns = append(table.Columns, col)
+		table.PrimaryCols = append(table.PrimaryCols, col.Name)
+		table.References = append(table.References, schema.Reference{
+			Name:                 m.foreignKeyName(table.Name, key.Relation, table.Name, col.Name),
+			TableName:            table.Name,
+			ColumnName:           col.Name,
+			ReferencedTableName:  key.Table,
+			ReferencedColumnName: key.Column.Name,
+			DeleteOption:         join.DeleteOption,
+			UpdateOption:         join.UpdateOption,
+		})
+	}
+	return table, nil
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+	"testing"
+)
+
+type Member struct {
+	ID        uint       `gorm:"primaryKey"`
+	Friends   []Member   `gorm:"many2many:member_friends"`
+	Mentors   []Member   `gorm:"many2many:member_mentors;joinForeignKey:MenteeID;joinReferences:MentorID"`
+	Languages []Language `gorm:"many2many:member_languages"`
+}
+
+type Language struct {
+	Code string `gorm:"primaryKey;type:varchar(8)"`
+}
+
+// Self referencing relation whose field has the name of the model
+type Node struct {
+	ID   uint   `gorm:"primaryKey"`
+	Node []Node `gorm:"many2many:node_links"`
+}
+
+func TestJoinTables(t *testing.T) {
+	m := &Migrator{Dialect: MySQLDialect{}}
+	tables, err := m.ParseTablesFromStructs(Member{}, Language{}, Node{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		table      string
+		columns    []string
+		references []string // Constraint name, column and referenced table. e.g. "fk_a_b:b_id->bs"
+	}{
+		{"member_friends", []string{"member_id", "friend_id"},
+			[]string{"fk_member_friends_member:member_id->members", "fk_member_friends_friends:friend_id->members"}},
+		{"member_mentors", []string{"mentee_id", "mentor_id"},
+			[]string{"fk_member_mentors_member:mentee_id->members", "fk_member_mentors_mentors:mentor_id->members"}},
+		{"member_languages", []string{"member_id", "language_code"},
+			[]string{"fk_member_languages_member:member_id->members", "fk_member_languages_language:language_code->languages"}},
+		{"node_links", []string{"node_id", "node_id_reference"},
+			[]string{"fk_node_links_node:node_id->nodes", "fk_node_links_node_id_reference:node_id_reference->nodes"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.table, func(t *testing.T) {
+			i := slices.IndexFunc(tables, func(table *schema.Table) bool { return table.Name == test.table })
+			if i == -1 {
+				t.Fatalf("join table %s is not created, tables are %v", test.table, tableNames(tables))
+			}
+			table := tables[i]
+
+			if got := columnNames(table); !slices.Equal(got, test.columns) {
+				t.Errorf("columns %v, want %v", got, test.columns)
+			}
+			if !slices.Equal(table.PrimaryCols, test.columns) {
+				t.Errorf("primary key %v, want %v", table.PrimaryCols, test.columns)
+			}
+			var references []string
+			for _, r := range table.References {
+				references = append(references, r.Name+":"+r.ColumnName+"->"+r.ReferencedTableName)
+			}
+			if !slices.Equal(references, test.references) {
+				t.Errorf("references %v, want %v", references, test.references)
+			}
+		})
+	}
+}
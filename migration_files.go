@@ -0,0 +1,77 @@
+package migrator
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Matches migration file names. e.g. "3_add_users.up.sql"
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+
+type migrationFile struct {
+	Version  int64
+	Name     string
+	UpPath   string
+	DownPath string
+}
+
+// Returns the migration files in the given directory ordered by version.
+// Files that don't follow the "<version>_<name>.[up/down].sql" format are ignored.
+// If more than one migration has the same version, '*VersionError' is returned
+func readMigrationFiles(dir string) ([]*migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migration directory: %w", err)
+	}
+
+	var files []*migrationFile
+	prefixes := make(map[int64]string) // version -> version as written in the file name, "1" and "01" are the same version
+	var duplicates []int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing version of %s: %w", entry.Name(), err)
+		}
+
+		i := slices.IndexFunc(files, func(f *migrationFile) bool { return f.Version == version })
+		if i == -1 {
+			files = append(files, &migrationFile{Version: version, Name: match[2]})
+			prefixes[version] = match[1]
+			i = len(files) - 1
+		}
+		file := files[i]
+		if file.Name != match[2] || prefixes[version] != match[1] {
+			if !slices.Contains(duplicates, version) {
+				duplicates = append(duplicates, version)
+			}
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if match[3] == "up" {
+			file.UpPath = path
+		} else {
+			file.DownPath = path
+		}
+	}
+
+	if len(duplicates) > 0 {
+		slices.Sort(duplicates)
+		return nil, &VersionError{Dir: dir, Duplicates: duplicates}
+	}
+
+	slices.SortFunc(files, func(a, b *migrationFile) int { return cmp.Compare(a.Version, b.Version) })
+	return files, nil
+}
@@ -1,14 +1,17 @@
 package migrator
 
 import (
+	"bufio"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/AkifSahn/migrator/schema"
-	"github.com/AkifSahn/migrator/utils"
-	"log"
+	"io"
 	"os"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -16,225 +19,298 @@ import (
 
 type Migrator struct {
 	DB             *sql.DB
+	Dialect        Dialect
 	SchemaName     string
 	Relations      []schema.Reference
-	CurrentVersion int
+	CurrentVersion int64
+	MigrationsDir  string          // Directory of the migration files. Applied by 'Up', 'Down' and 'Goto', replayed when there is no database connection
+	Naming         NamingStrategy  // Names of the tables, columns and constraints. gorm's default naming is used if nil
+	Writer         MigrationWriter // Format of the saved migration files. golang-migrate's format is used if nil
+
+	// Destructive changes, like dropping a table, are refused unless this is true, they are listed in 'AllowDrops'
+	// or they are allowed by an annotation. See 'ALLOW_DROP_ANNOTATION'
+	AllowDestructive bool
+
+	// Tables and columns whose destructive changes are allowed, e.g. "users" or "users.email".
+	// Set it only for the run that creates the migration, so a later table or column of the same name is guarded again
+	AllowDrops []string
+
+	// Pairs a dropped column with an added column of the same definition in the same table as a renamed column.
+	// Each pair is confirmed by 'Confirm'. If 'Confirm' is nil, the migration fails with '*RenameError'
+	DetectRenames bool
+
+	// Applied migrations of 'Up', 'Down' and 'Goto' are reported into Log, nothing is written if it is nil
+	Log io.Writer
+
+	// Asks the user a yes or no question. 'MigrateAndSave' asks on the terminal if it is nil
+	Confirm func(question string) (bool, error)
+
+	// Glob patterns of the tables managed by migrator, e.g. "app_*". Every table is managed if empty.
+	// Unmanaged tables are never created, altered or dropped, but models can still reference them
+	IncludeTables []string
+
+	// Names or glob patterns of the tables that are not managed by migrator, even if they are included.
+	// Tables that have 'UNMANAGED_TABLE_MARKER' in their comment are not managed either
+	ExcludeTables []string
+
+	// Column types of go types, they are used before any other rule. See 'RegisterDataType'
+	DataTypes map[reflect.Type]string
+
+	// The '*gorm.DB' passed to the 'GormDBDataType' methods of field types. Such methods are not called if it is nil
+	GormDB interface{}
+
+	relationFields []relationField // Relation fields of the parsed models, resolved into foreign keys after the models
+	joinTables     []joinTable     // Many2many relations of the parsed models, their join tables are created after the models
 }
 
-// Returns a new migrator instance that is connected to the database by given dsn
-func NewMigrator(dsn, schemaName string) *Migrator {
+// Returns a new MySQL migrator instance that is connected to the database by given dsn
+func NewMigrator(dsn, schemaName string) (*Migrator, error) {
+	return NewMigratorWithDialect(MySQLDialect{}, dsn, schemaName)
+}
+
+// Returns a new migrator instance for the given dialect that is connected to the database by given dsn.
+// The database/sql driver of the dialect must be imported by the caller, except for MySQL
+func NewMigratorWithDialect(dialect Dialect, dsn, schemaName string) (*Migrator, error) {
 
 	// Open a connection to the database
-	db, err := sql.Open("mysql", dsn)
+	db, err := sql.Open(dialect.DriverName(), dsn)
 	if err != nil {
-		fmt.Println("Error opening connection:", err)
-		return nil
+		return nil, fmt.Errorf("opening connection: %w", err)
 	}
 
 	// Check if the connection is alive
 	if err := db.Ping(); err != nil {
-		fmt.Println("Error pinging database:", err)
-		return nil
+		db.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	fmt.Println("Successfully connected to the database")
-
 	m := &Migrator{
 		DB:         db,
+		Dialect:    dialect,
 		SchemaName: schemaName,
 		Relations:  make([]schema.Reference, 0),
 	}
 	version, err := m.getCurrentVersion()
 	if err != nil {
-		fmt.Println("Error getting current database version!: ", err.Error())
-		return nil
+		db.Close()
+		return nil, fmt.Errorf("getting current database version: %w", err)
 	}
 	m.CurrentVersion = version
-	return m
+	return m, nil
 }
 
 // Creates and saves migration script based on the given target models and current state of the database.
-// If dryRun flag is true Migrator prints the migration script and exits
-func (m *Migrator) MigrateAndSave(dryRun bool, saveDirectory string, targetModels ...interface{}) {
-	// Desired database state
-	dst := m.ParseTablesFromStructs(targetModels...)
-
-	upScript, downScript := m.CreateMigration(dst, true)
-
-	// Check if any migration is necessary
-	if upScript == "" {
-		fmt.Println("No migration necessary!")
-		return
-	}
-
-	if !dryRun {
-		var migrationName string
-
-		fmt.Println("Current database version is: ", m.CurrentVersion)
-		fmt.Println("Setting migration version as: ", m.CurrentVersion+1)
-		fmt.Print("Enter the migration name: ")
-		_, err := fmt.Scan(&migrationName)
-		if err != nil {
-			fmt.Println("Cannot scan given input!")
-			return
+// If dryRun flag is true Migrator prints the migration script and exits.
+// Name of the migration is read from stdin and the prompts are written into stdout, use 'MigrateWithOptions' to run without a terminal
+// or to write into another 'Options.Output'. Offline migrators replay the migrations in the saveDirectory instead of reading the database
+func (m *Migrator) MigrateAndSave(dryRun bool, saveDirectory string, targetModels ...interface{}) error {
+	stdin := bufio.NewReader(os.Stdin)
+	if m.Confirm == nil {
+		m.Confirm = promptConfirm(stdin, os.Stdout)
+		defer func() { m.Confirm = nil }()
+	}
+
+	opts := Options{Dir: saveDirectory, DryRun: dryRun, Output: os.Stdout}
+	_, err := m.migrateWithOptions(context.Background(), opts, func(out io.Writer) (string, error) {
+		fmt.Fprintln(out, "Current database version is: ", m.CurrentVersion)
+		fmt.Fprintln(out, "Setting migration version as: ", m.CurrentVersion+1)
+		fmt.Fprint(out, "Enter the migration name: ")
+		migrationName, err := stdin.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("reading migration name: %w", err)
+		}
+		name := slugify(migrationName)
+		if name == "" {
+			return "", fmt.Errorf("invalid migration name %q", strings.TrimSpace(migrationName))
 		}
+		return name, nil
+	}, targetModels...)
+	return err
+}
 
-		migrationName = fmt.Sprintf("%d_%s", m.CurrentVersion+1, migrationName)
-		fmt.Printf("Migrations are saved as: %s.[up/down].sql\n", migrationName)
+// Returns the version stored in the version table, 0 if the database has no version table yet
+func (m *Migrator) getCurrentVersion() (int64, error) {
+	ctx := context.Background()
+	names, err := m.Dialect.TableNames(ctx, m.DB, m.SchemaName)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(names, VERSION_TABLE) {
+		return 0, nil
+	}
 
-		os.WriteFile(fmt.Sprintf("%s/%s.up.sql", saveDirectory, migrationName), []byte(upScript), 0644)
-		os.WriteFile(fmt.Sprintf("%s/%s.down.sql", saveDirectory, migrationName), []byte(downScript), 0644)
-	} else {
-		fmt.Println("*****UP SCRIPT*****")
-		fmt.Println(upScript)
-		fmt.Println("*****DOWN SCRIPT*****")
-		fmt.Println(downScript)
-		fmt.Println("--dry-run argument is passed, no migration file created!")
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return 0, err
 	}
+	defer conn.Close()
 
+	version, _, err := readVersion(ctx, conn)
+	return version, err
 }
 
-func (m *Migrator) getCurrentVersion() (int, error) {
-	row := m.DB.QueryRow("SELECT version FROM schema_migrations")
+// Parses the current database state into 'schema.Table' struct. Only the managed tables are returned
+func (m *Migrator) GetTables() ([]*schema.Table, error) {
+	tables, _, err := m.currentTables(context.Background(), m.MigrationsDir)
+	return tables, err
+}
 
-	var version int
-	if err := row.Scan(&version); err != nil {
-		fmt.Println("Setting version as 0, since there is no version being stored in database!")
-		return 0, nil
+// Returns the current managed tables and the names of the unmanaged tables from the database,
+// or from the migration files of the directory if the migrator is offline
+func (m *Migrator) currentTables(ctx context.Context, dir string) ([]*schema.Table, []string, error) {
+	if m.IsOffline() {
+		tables, err := m.replayMigrations(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m.splitManagedTables(tables)
 	}
-
-	return version, nil
-
+	return m.getTables(ctx)
 }
 
-// Parses the current database state into 'schema.Table' struct
-func (m *Migrator) GetTables() []*schema.Table {
-	rows, err := m.DB.Query("SHOW TABLES")
+// Returns the managed tables of the database and the names of the unmanaged tables. Unmanaged tables are not introspected
+func (m *Migrator) getTables(ctx context.Context) ([]*schema.Table, []string, error) {
+	names, err := m.Dialect.TableNames(ctx, m.DB, m.SchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+	comments, err := m.Dialect.TableComments(ctx, m.DB, m.SchemaName)
 	if err != nil {
-		fmt.Println("Error querying tables: ", err)
-		return nil
+		return nil, nil, err
 	}
-	defer rows.Close()
 
 	var tables []*schema.Table
-	for rows.Next() {
-		var table schema.Table
-		if err := rows.Scan(&table.Name); err != nil {
-			fmt.Println("Error scanning row: ", err)
-			return nil
+	var unmanaged []string
+	for _, name := range names {
+		managed, err := m.isManaged(name, comments[name])
+		if err != nil {
+			return nil, nil, err
 		}
-
-		if table.Name == "schema_migrations" {
+		if !managed {
+			unmanaged = append(unmanaged, name)
 			continue
 		}
-		table.Columns = m.DescribeTable(table.Name)
-		table.References = m.GetReferences(table.Name)
 
-		// Set primaryCols table
-		for _, c := range table.Columns {
-			if c.PrimaryKey {
-				table.PrimaryCols = append(table.PrimaryCols, c.Name)
+		table := schema.Table{Name: name, Comment: comments[name]}
+		table.Columns, err = m.Dialect.DescribeTable(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		table.References, err = m.Dialect.GetReferences(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		table.PrimaryCols, err = m.Dialect.GetPrimaryKey(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if namer, ok := m.Dialect.(PrimaryKeyNamer); ok && len(table.PrimaryCols) > 0 {
+			table.PrimaryKeyName, err = namer.GetPrimaryKeyName(ctx, m.DB, m.SchemaName, table.Name)
+			if err != nil {
+				return nil, nil, err
 			}
 		}
 
 		// Fill the 'table.IndexToUniqueCols'
-		table.IndexToUniqueCols = m.getUniqueIndexes(table.Name)
+		table.IndexToUniqueCols, err = m.Dialect.GetUniqueIndexes(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		table.Indexes, err = m.Dialect.GetIndexes(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		table.Checks, err = m.Dialect.GetChecks(ctx, m.DB, m.SchemaName, table.Name)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		// Set foreign keys for columns based on reference information
 		for _, r := range table.References {
-			col := table.Columns[slices.IndexFunc(table.Columns, func(c *schema.Column) bool { return c.Name == r.ColumnName })]
-			col.ForeignKey = true
+			i := slices.IndexFunc(table.Columns, func(c *schema.Column) bool { return c.Name == r.ColumnName })
+			if i == -1 {
+				return nil, nil, &IntrospectionError{Table: table.Name, Err: fmt.Errorf("foreign key column %s does not exist", r.ColumnName)}
+			}
+			table.Columns[i].ForeignKey = true
 		}
 
 		tables = append(tables, &table)
 	}
 
-	return tables
+	return tables, unmanaged, nil
 }
 
-// Returns the 'map[indexName] -> []ColumnName' for the current database state
-func (m *Migrator) getUniqueIndexes(tableName string) map[string][]string {
-	rows, err := m.DB.Query(fmt.Sprintf("SHOW INDEX FROM %s WHERE Non_unique=0 AND Key_name != 'PRIMARY'", tableName))
-	if err != nil {
-		fmt.Println("Error querying the indexes: ", err)
-		return nil
-	}
-	defer rows.Close()
-
-	var table, keyName, columnName string
-	var discard interface{}
-
-	indexToCols := make(map[string][]string)
+// Parses given structs into `schema.Table` struct.
+// Structs can be given in any order, relations are resolved after all of them are parsed
+func (m *Migrator) ParseTablesFromStructs(dst ...interface{}) ([]*schema.Table, error) {
+	m.Relations = m.Relations[:0]
+	m.relationFields = m.relationFields[:0]
+	m.joinTables = m.joinTables[:0]
 
-	for rows.Next() {
-		// Only scan the necessary fields, discard others
-		if err := rows.Scan(&table, &discard, &keyName, &discard,
-			&columnName, &discard, &discard, &discard, &discard,
-			&discard, &discard, &discard, &discard, &discard,
-			&discard); err != nil {
-			fmt.Println("Error scanning the row: ", err)
-			return nil
-		}
-		keyName = strings.TrimPrefix(keyName, "uc.")
-		if _, exists := indexToCols[keyName]; !exists {
-			indexToCols[keyName] = []string{columnName}
-		} else {
-			indexToCols[keyName] = append(indexToCols[keyName], columnName)
+	var tables []*schema.Table
+	for _, item := range dst {
+		table, err := m.parseTableFromStruct(item)
+		if err != nil {
+			return nil, err
 		}
+		tables = append(tables, table)
 	}
 
-	return indexToCols
-}
-
-// Parses given structs into `schema.Table` struct.
-// Given structs must be in order so that referenced table comes before the foreignKey table
-func (m *Migrator) ParseTablesFromStructs(dst ...interface{}) []*schema.Table {
-	var tables []*schema.Table
-	for _, item := range dst {
-		tables = append(tables, m.parseTableFromStruct(item))
+	if err := m.resolveRelations(tables); err != nil {
+		return nil, err
 	}
 
-	return tables
+	return m.appendJoinTables(tables)
 }
 
-func (m *Migrator) parseTableFromStruct(dst interface{}) *schema.Table {
+func (m *Migrator) parseTableFromStruct(dst interface{}) (*schema.Table, error) {
 	table := schema.Table{}
-	val := reflect.ValueOf(dst)
 	typ := reflect.TypeOf(dst)
 
-	if typ.Kind() != reflect.Struct {
-		fmt.Println("Expected a struct, but got: ", typ.Kind())
-		return nil
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, &ModelError{Model: fmt.Sprint(typ), Reason: "expected a struct"}
 	}
 
-	table.Name = typ.Name()
+	table.Name = m.tableName(typ)
 	table.IndexToUniqueCols = make(map[string][]string)
 
-	// iterate each field in the struct and parse them into 'schema.Column' struct
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
+	renamedFrom, err := tableRenamedFrom(dst)
+	if err != nil {
+		return nil, err
+	}
+	table.RenamedFrom = renamedFrom
 
-		col := m.parseStructField(&table, field)
+	// iterate each field in the struct and parse them into 'schema.Column' struct
+	for _, field := range m.modelFields(typ, "", "") {
+		col, err := m.parseStructField(&table, typ.Name(), field)
+		if err != nil {
+			return nil, &TagParseError{Model: typ.Name(), Field: field.Path, Tag: field.Tag.Get("gorm"), Err: err}
+		}
 		if col != nil {
 			table.Columns = append(table.Columns, col)
 		}
 	}
 
 	if table.GetPrimaryKeyColumn() == nil {
-		log.Fatalf("A table must have a primary key!. Table: %s\n", table.Name)
+		return nil, &ModelError{Model: typ.Name(), Reason: "a table must have a primary key"}
 	}
 
-	table.Name = utils.Pluralize(utils.ToMysqlName(table.Name))
+	table.SplitUniqueIndexes()
 
-	return &table
+	return &table, nil
 }
 
-// Parses the given 'reflect.StructField' into 'schema.Column'
-func (m *Migrator) parseStructField(table *schema.Table, field reflect.StructField) *schema.Column {
+// Parses the given struct field of the 'modelName' struct into 'schema.Column'.
+// Returns nil column if the field describes a relation instead of a column
+func (m *Migrator) parseStructField(table *schema.Table, modelName string, field modelField) (*schema.Column, error) {
 	var col schema.Column
 
 	col.TableName = table.Name
-	col.Name = field.Name
+	col.FieldName = field.Name
+	col.Name = field.Prefix + m.namer().ColumnName(table.Name, field.Name)
 
 	col.Null = "YES"
 	col.PrimaryKey = false
@@ -243,235 +319,344 @@ func (m *Migrator) parseStructField(table *schema.Table, field reflect.StructFie
 	col.DefaultValue = sql.NullString{String: "", Valid: false}
 	col.Extra = ""
 
-	if !(strings.ToLower(col.ColumnType) == "tinyint" ||
-		strings.ToLower(col.ColumnType) == "smallint" ||
-		strings.ToLower(col.ColumnType) == "mediumint" ||
-		strings.ToLower(col.ColumnType) == "int" ||
-		strings.ToLower(col.ColumnType) == "bigint") &&
-		strings.Contains(col.Extra, "auto_increment") {
-
-		log.Fatalf("Auto increment can only be applied to integer type columns! %s.%s\n", table.Name, col.Name)
-	}
-
 	// By default, reference options are cascade
 	deleteOption := schema.CASCADE_OPTION
 	updateOption := schema.CASCADE_OPTION
 
-	var fkColumnName string
-	var fkTableName string
-	var isFkUnique bool
-	var referencedTableName string
-	var referencedColumnName string
+	// Relation fields are resolved into a foreign key after every model is parsed. See 'relationField'
+	var foreignKey, references string
 
-	setRelation := false
+	// Many2many relations are migrated as a join table instead of a foreign key
+	var many2many, joinForeignKey, joinReferences string
 
-	var indexName string
+	// Settings that depend on the final column name or type are applied after every setting is read,
+	// so their order in the tag doesn't matter. e.g. "index;column:user_name"
+	var primaryKey, unique bool
+	var indexes, uniqueIndexes, checks []string
+	var size, precision, scale int
+	var serializer string
 
-	// Set default foreign key properties
-	if field.Type.Kind() == reflect.Struct {
-		// is this good?
-		if _, err := utils.ToMysqlDataType(field.Type.String()); err != nil {
-			referencedTableName = table.Name
-			referencedColumnName = table.GetPrimaryKeyColumn().Name
-
-			fkTableName = field.Name
-			fkColumnName = referencedTableName + "ID"
-			setRelation = true
-			isFkUnique = true
+	for _, setting := range parseTagSettings(field.Tag.Get("gorm")) {
+		var err error
+		switch value := setting.Value; setting.Key {
+		case "COLUMN":
+			col.Name = field.Prefix + value
+		case "TYPE":
+			col.ColumnType = value
+		case "SIZE":
+			size, err = tagInt(setting)
+		case "PRECISION":
+			precision, err = tagInt(setting)
+		case "SCALE":
+			scale, err = tagInt(setting)
+		case "PRIMARYKEY", "PRIMARY_KEY":
+			primaryKey = tagBool(value)
+		case "UNIQUE":
+			unique = tagBool(value)
+		case "NOT NULL", "NOTNULL":
+			if tagBool(value) {
+				col.Null = "NO"
+			}
+		case "AUTOINCREMENT", "AUTO_INCREMENT":
+			col.Extra = ""
+			if tagBool(value) {
+				col.Extra = "auto_increment"
+			}
+		case "AUTOINCREMENTINCREMENT":
+			// Migrations can't set the increment of a single column, other increments than 1 would be silently lost
+			var increment int
+			if increment, err = tagInt(setting); err == nil && increment != 1 {
+				err = fmt.Errorf("autoincrementincrement %d is not supported, auto increment columns are incremented by 1", increment)
+			}
+		case "DEFAULT":
+			col.DefaultValue = sql.NullString{String: value, Valid: true}
+		case "COMMENT":
+			col.Comment = value
+		case "CHECK":
+			checks = append(checks, value)
+		case "SERIALIZER":
+			serializer = strings.TrimSpace(value)
+		case "<-", "->":
+			// Permissions only change how gorm reads and writes the field, the column is migrated all the same
+		case "INDEX":
+			indexes = append(indexes, value)
+		case "UNIQUEINDEX":
+			uniqueIndexes = append(uniqueIndexes, value)
+		case "CONSTRAINT":
+			err = parseConstraintTag(value, &deleteOption, &updateOption)
+		case "FOREIGNKEY":
+			// Override the default foreign key field
+			foreignKey = value
+		case "REFERENCES":
+			// Override the default referenced field
+			references = value
+		case "MANY2MANY":
+			many2many = m.namer().JoinTableName(value)
+		case "JOINFOREIGNKEY":
+			joinForeignKey = value
+		case "JOINREFERENCES":
+			joinReferences = value
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	if field.Type.Kind() == reflect.Slice {
-		referencedTableName = table.Name
-		referencedColumnName = table.GetPrimaryKeyColumn().Name
-
-		fkTableName = field.Name
-		fkColumnName = referencedTableName + "ID"
-		setRelation = true
-		isFkUnique = false
+	// Options of migrator itself. e.g. `migrator:"renamedFrom:full_name"`
+	renamedFrom, err := parseMigratorTag(field.Tag.Get("migrator"))
+	if err != nil {
+		return nil, err
 	}
+	col.RenamedFrom = renamedFrom
 
-	// Parsing tag fields accordingly
-	if field.Tag.Get("gorm") != "" {
-		for _, v := range strings.Split(field.Tag.Get("gorm"), ";") { // split gorm fields by ';'
-			if strings.Contains(v, "type") {
-				col.ColumnType = strings.Split(v, ":")[1]
-
-			} else if strings.Contains(v, "constraint") {
-				for _, s := range strings.Split(v, ",") {
-					if strings.Contains(s, "OnUpdate") {
-						updateOption = schema.ReferenceOption(strings.ToUpper(strings.Split(v, ":")[2]))
-					}
-					if strings.Contains(s, "OnDelete") {
-						deleteOption = schema.ReferenceOption(strings.ToUpper(strings.Split(v, ":")[2]))
-					}
-				}
-
-			} else if v == "primaryKey" {
-				table.PrimaryCols = append(table.PrimaryCols, utils.ToMysqlName(col.Name))
-				col.PrimaryKey = true
-				col.Null = "NO"
-
-			} else if strings.Contains(v, "uniqueIndex") {
-				if !col.PrimaryKey {
-					indexName = fmt.Sprintf("%s.%s", utils.Pluralize(utils.ToMysqlName(table.Name)), utils.ToMysqlName(col.Name))
-					if len(strings.Split(v, ":")) > 1 { // Handle custom unique index name
-						indexName = fmt.Sprintf("%s.%s", utils.Pluralize(utils.ToMysqlName(col.TableName)), strings.Split(v, ":")[1])
-					}
-					if _, exists := table.IndexToUniqueCols[indexName]; !exists {
-						table.IndexToUniqueCols[indexName] = []string{utils.ToMysqlName(col.Name)}
-					} else {
-						table.IndexToUniqueCols[indexName] = append(table.IndexToUniqueCols[indexName], utils.ToMysqlName(col.Name))
-					}
-					col.UniqueIndex = true
-				}
-				col.UniqueIndex = true
-			} else if v == "not null" {
-				col.Null = "NO"
-			} else if v == "auto_increment" {
-				col.Extra = strings.TrimSpace(col.Extra + " " + "auto_increment")
-			} else if strings.Contains(v, "foreignKey") {
-				// Override the default foreign key column
-				fkColumnName = strings.Split(v, ":")[1]
-			} else if v == "references" {
-				// Override the default referenced column
-				referencedColumnName = strings.Split(v, ":")[1]
-			} else if strings.Contains(v, "default") {
-				// TODO: type checking for default option
-				col.DefaultValue.String = strings.Split(v, ":")[1]
-				col.DefaultValue.Valid = true
-			}
+	if many2many != "" {
+		if field.Type.Kind() != reflect.Slice {
+			return nil, errors.New("many2many relation must be a slice of models")
+		}
+		m.joinTables = append(m.joinTables, joinTable{
+			Name:         many2many,
+			Table:        table,
+			ModelName:    modelName,
+			FieldName:    field.Name,
+			Related:      relatedType(field.Type),
+			ForeignKey:   joinForeignKey,
+			Reference:    joinReferences,
+			DeleteOption: deleteOption,
+			UpdateOption: updateOption,
+		})
+		return nil, nil
+	}
+
+	// Serialized fields are stored in a single column whatever their type is
+	isRelation := relatedType(field.Type).Kind() == reflect.Struct && !m.isDataType(field.Type)
+	if isRelation && serializer == "" {
+		m.relationFields = append(m.relationFields, relationField{
+			Table:        table,
+			ModelName:    modelName,
+			FieldName:    field.Name,
+			Related:      relatedType(field.Type),
+			Many:         field.Type.Kind() == reflect.Slice,
+			ForeignKey:   foreignKey,
+			References:   references,
+			DeleteOption: deleteOption,
+			UpdateOption: updateOption,
+		})
+		return nil, nil
+	}
+
+	if primaryKey {
+		table.PrimaryCols = append(table.PrimaryCols, col.Name)
+		col.PrimaryKey = true
+		col.Null = "NO"
+	}
+
+	for _, value := range indexes {
+		if err := m.parseIndexTag(table, field.Name, col.Name, value, false); err != nil {
+			return nil, err
 		}
 	}
-
-	for _, rel := range m.Relations {
-		if utils.Pluralize(utils.ToMysqlName(rel.TableName)) == utils.Pluralize(utils.ToMysqlName(table.Name)) && utils.ToMysqlName(rel.ColumnName) == utils.ToMysqlName(col.Name) {
-			table.References = append(table.References, rel)
-			col.ForeignKey = true
-			if rel.IsUnique && !col.PrimaryKey {
-				if indexName == "" {
-					indexName = fmt.Sprintf("%s.%s", utils.Pluralize(utils.ToMysqlName(table.Name)), utils.ToMysqlName(col.Name))
-					table.IndexToUniqueCols[indexName] = []string{utils.ToMysqlName(col.Name)}
-				}
-				col.UniqueIndex = true
+	for _, value := range uniqueIndexes {
+		if strings.Contains(value, ",") {
+			// Unique indexes with options can't be expressed as a unique constraint
+			if err := m.parseIndexTag(table, field.Name, col.Name, value, true); err != nil {
+				return nil, err
 			}
-			break
+		} else if !col.PrimaryKey {
+			indexName := m.uniqueIndexName(table.Name, field.Name, col.Name, value)
+			table.IndexToUniqueCols[indexName] = append(table.IndexToUniqueCols[indexName], col.Name)
 		}
+		col.UniqueIndex = true
+	}
+	if unique && !col.PrimaryKey {
+		table.IndexToUniqueCols[m.namer().UniqueName(table.Name, field.Name)] = []string{col.Name}
+		col.UniqueIndex = true
 	}
 
-	// Mysqlize table and column names
-	referencedColumnName = utils.ToMysqlName(referencedColumnName)
-	fkColumnName = utils.ToMysqlName(fkColumnName)
-	col.Name = utils.ToMysqlName(col.Name)
+	for _, value := range checks {
+		check, err := m.parseCheckTag(table, field.Name, value)
+		if err != nil {
+			return nil, err
+		}
+		table.Checks = append(table.Checks, check)
+	}
 
-	col.TableName = utils.Pluralize(utils.ToMysqlName(col.TableName))
-	referencedTableName = utils.Pluralize(utils.ToMysqlName(referencedTableName))
-	fkTableName = utils.Pluralize(utils.ToMysqlName(fkTableName))
+	// SQLite has no column comments, gorm doesn't write them either
+	if _, ok := m.Dialect.(SQLiteDialect); ok {
+		col.Comment = ""
+	}
 
-	if setRelation {
-		m.newRelation(fkTableName, fkColumnName, referencedTableName, referencedColumnName, deleteOption, updateOption, isFkUnique)
-	} else if col.ColumnType == "" {
-		var err error
-		col.ColumnType, err = utils.ToMysqlDataType(field.Type.String())
+	// Explicit 'type' is used as it is, serializers store their value as a string the same way gorm does
+	if col.ColumnType == "" {
+		goType := field.Type
+		if serializer != "" {
+			goType = reflect.TypeOf("")
+		}
+		// Auto increment values fit the signed type, postgres has no unsigned integers. e.g. bigint instead of numeric(20)
+		if _, ok := m.Dialect.(PostgresDialect); ok && isUnsigned(goType) && strings.Contains(col.Extra, "auto_increment") {
+			goType = reflect.TypeOf(int64(0))
+		}
+		columnType, err := m.dataType(goType)
 		if err != nil {
-			log.Fatalf("Cannot resolve the field type to a mysql type. Table: %s, Column: %s, Type: %s", table.Name, col.Name, field.Type.String())
+			return nil, err
 		}
+		col.ColumnType = sizedColumnType(columnType, size, precision, scale)
 	}
 
-	if !setRelation {
-		return &col
+	// Default value is checked against the column type, 'type' may come after 'default' in the tag
+	if col.DefaultValue.Valid {
+		if col.DefaultValue, err = schema.NormalizeDefault(col.ColumnType, col.DefaultValue.String, false); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	if strings.Contains(col.Extra, "auto_increment") && !slices.Contains(
+		[]string{"tinyint", "smallint", "mediumint", "int", "integer", "bigint"},
+		schema.ParseColumnType(col.ColumnType).Base) {
+
+		return nil, errors.New("auto increment can only be applied to integer type columns")
+	}
+
+	return &col, nil
 }
 
-// Creates a new relation and appends to the relations list of migrator object
-func (m *Migrator) newRelation(tableName, columnName, referencedTableName, referencedColumnName string, onDelete, onUpdate schema.ReferenceOption, isUnique bool) {
-	m.Relations = append(m.Relations, schema.Reference{
-		TableName:            tableName,
-		ColumnName:           columnName,
-		ReferencedTableName:  referencedTableName,
-		ReferencedColumnName: referencedColumnName,
-		DeleteOption:         onDelete,
-		UpdateOption:         onUpdate,
-		IsUnique:             isUnique,
-	})
+// Returns true if the type is an unsigned integer, or a pointer to one
+func isUnsigned(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() >= reflect.Uint && typ.Kind() <= reflect.Uintptr
 }
 
-func (m *Migrator) GetReferences(tableName string) []schema.Reference {
-	query := fmt.Sprintf(
-		`SELECT rc.UPDATE_RULE, rc.DELETE_RULE, rc.TABLE_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME
-        FROM 
-        INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
-        JOIN 
-        INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
-        ON rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
-        AND rc.TABLE_NAME = kcu.TABLE_NAME
-        WHERE 
-        rc.CONSTRAINT_SCHEMA = '%s' AND kcu.TABLE_SCHEMA = '%s' AND rc.TABLE_NAME = '%s' AND kcu.REFERENCED_TABLE_NAME IS NOT NULL; `,
-		m.SchemaName, m.SchemaName, tableName)
-
-	rows, err := m.DB.Query(query)
-	if err != nil {
-		log.Fatal("Cannot get references from database!: ", err)
-		return nil
+// Returns the model type of a relation field. e.g. 'User' for '[]*User'
+func relatedType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
 	}
-	defer rows.Close()
+	return typ
+}
 
-	var references []schema.Reference
-	for rows.Next() {
-		var reference schema.Reference
-		err := rows.Scan(&reference.UpdateOption, &reference.DeleteOption, &reference.TableName, &reference.ColumnName, &reference.ReferencedTableName, &reference.ReferencedColumnName)
-		if err != nil {
-			log.Fatal("Error scanning the row: ", err)
-			return nil
+// Parses the value of gorm 'index' tag into the indexes of the table.
+// e.g. "idx_name,priority:2,sort:desc,length:10,class:FULLTEXT".
+// Fields that use the same index name are merged into a composite index
+func (m *Migrator) parseIndexTag(table *schema.Table, fieldName, colName, value string, unique bool) error {
+	options := strings.Split(value, ",")
+	name := strings.TrimSpace(options[0])
+	if name == "" {
+		name = m.namer().IndexName(table.Name, fieldName)
+	}
+
+	// Same default priority as gorm
+	col := schema.IndexColumn{Name: colName, Priority: 10}
+	var indexType schema.IndexType
+	for _, option := range options[1:] {
+		key, val, _ := strings.Cut(option, ":")
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "":
+		case "priority":
+			priority, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("invalid index priority %q", val)
+			}
+			col.Priority = priority
+		case "length":
+			length, err := strconv.Atoi(val)
+			if err != nil || length < 0 {
+				return fmt.Errorf("invalid index length %q", val)
+			}
+			col.Length = length
+		case "sort":
+			switch strings.ToLower(val) {
+			case "asc":
+				col.Desc = false
+			case "desc":
+				col.Desc = true
+			default:
+				return fmt.Errorf("invalid index sort %q", val)
+			}
+		case "class":
+			switch strings.ToUpper(val) {
+			case "FULLTEXT", "SPATIAL":
+				indexType = schema.IndexType(strings.ToUpper(val))
+			case "UNIQUE":
+				unique = true
+			default:
+				return fmt.Errorf("invalid index class %q", val)
+			}
+		case "type":
+			indexType = schema.IndexType(strings.ToUpper(val))
+		case "unique":
+			unique = true
+		default:
+			return fmt.Errorf("index option %q is not supported", key)
 		}
+	}
 
-		references = append(references, reference)
+	index := table.GetIndex(name)
+	if index == nil {
+		index = &schema.Index{Name: name}
+		table.Indexes = append(table.Indexes, index)
 	}
+	index.Unique = index.Unique || unique
+	if indexType != "" {
+		index.Type = indexType
+	}
+	index.AddColumn(col)
 
-	return references
+	return nil
 }
 
-func (m *Migrator) DescribeTable(tableName string) []*schema.Column {
-	query := fmt.Sprintf("DESCRIBE %s", tableName)
-	rows, err := m.DB.Query(query)
-	if err != nil {
-		fmt.Println("Error describing table: ", err)
-		return nil
+// Returns the foreign keys declared on the given table
+func (m *Migrator) GetReferences(tableName string) ([]schema.Reference, error) {
+	if m.IsOffline() {
+		return nil, errors.New("reading references requires a database connection")
 	}
-	defer rows.Close()
-
-	var cols []*schema.Column
-
-	var key string
-	for rows.Next() {
-		var col schema.Column
-		col.TableName = tableName
-
-		err := rows.Scan(&col.Name, &col.ColumnType, &col.Null, &key, &col.DefaultValue, &col.Extra)
-		if err != nil {
-			fmt.Println("Error scanning the row: ", err)
-			return nil
-		}
+	return m.Dialect.GetReferences(context.Background(), m.DB, m.SchemaName, tableName)
+}
 
-		switch schema.Key(key) {
-		case schema.PRIMARY_KEY:
-			col.PrimaryKey = true
-		case schema.UNIQUE_INDEX:
-			col.UniqueIndex = true
-		}
+// Returns the columns of the given table
+func (m *Migrator) DescribeTable(tableName string) ([]*schema.Column, error) {
+	if m.IsOffline() {
+		return nil, errors.New("describing a table requires a database connection")
+	}
+	return m.Dialect.DescribeTable(context.Background(), m.DB, m.SchemaName, tableName)
+}
 
-		cols = append(cols, &col)
+// Compares the current state of the database schema with the given 'dst' schema.
+// Creates and returns the migration script that will bring database to the desired state.
+// If verbose is true the scripts are also printed into stdout
+func (m *Migrator) CreateMigration(dst []*schema.Table, verbose bool) (string, string, error) {
+	upScript, downScript, err := m.createMigration(context.Background(), m.MigrationsDir, dst)
+	if err != nil || !verbose {
+		return upScript, downScript, err
 	}
 
-	return cols
+	if upScript == "" {
+		fmt.Println("No migration necessary!")
+	} else {
+		fmt.Printf("*****UP SCRIPT*****\n%s\n*****DOWN SCRIPT*****\n%s\n", upScript, downScript)
+	}
+	return upScript, downScript, nil
 }
 
-// Compares the current state of the database schema with the given 'dst' schema.
-// Creates and returns the migration script that will bring database to the desired state
-func (m *Migrator) CreateMigration(dst []*schema.Table, verbose bool) (string, string) {
-	dbTables := m.GetTables()
+// Returns the migration scripts from the current tables to the 'dst' tables.
+// Offline migrators read the current tables from the migrations of the given directory
+func (m *Migrator) createMigration(ctx context.Context, dir string, dst []*schema.Table) (string, string, error) {
+	dbTables, unmanaged, err := m.currentTables(ctx, dir)
+	if err != nil {
+		return "", "", err
+	}
+	dst, err = m.managedModels(dst, unmanaged)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Renamed tables are compared with the models under their new names
+	renamed, err := renamedTables(dbTables, dst)
+	if err != nil {
+		return "", "", err
+	}
+	dbTables = renameTables(dbTables, renamed)
 
 	var sbUp strings.Builder
 	var sbDown strings.Builder
@@ -500,70 +685,211 @@ func (m *Migrator) CreateMigration(dst []*schema.Table, verbose bool) (string, s
 		}
 	}
 
-	for _, t := range newTables {
-		sbUp.WriteString(m.CreateTableQuery(t))
+	var altered []*schema.TablePair
+	for _, name := range alteredTablesOrder {
+		altered = append(altered, alteredTables[name])
+	}
+	if m.DetectRenames {
+		if err := m.detectRenames(altered); err != nil {
+			return "", "", err
+		}
+	}
+	if err := m.checkDestructiveChanges(dir, deletedTables, altered); err != nil {
+		return "", "", err
+	}
+
+	// Referenced tables are created first and dropped last
+	newTables, newDeferred := sortTablesByDependency(newTables)
+	deletedTables, deletedDeferred := sortTablesByDependency(deletedTables)
+
+	// Tables are renamed before anything else and renamed back after everything else
+	var renamedOrder []string
+	for oldName := range renamed {
+		renamedOrder = append(renamedOrder, oldName)
+	}
+	slices.Sort(renamedOrder)
+	for _, oldName := range renamedOrder {
+		query, err := m.RenameTableQuery(oldName, renamed[oldName])
+		if err != nil {
+			return "", "", err
+		}
+		sbUp.WriteString(query)
+	}
+
+	if err := m.createTables(newTables, newDeferred, &sbUp); err != nil {
+		return "", "", err
 	}
 
 	// Create deleted tables in down script
-	for _, t := range deletedTables {
-		sbDown.WriteString(m.CreateTableQuery(t))
+	if err := m.createTables(deletedTables, deletedDeferred, &sbDown); err != nil {
+		return "", "", err
 	}
 
 	// Compare the tables that are not new or deleted to figure out if they are same
 	for _, i := range alteredTablesOrder {
 		v := alteredTables[i]
-		upMigrations := v.First.CompareWith(v.Second)
-		downMigrations := v.Second.CompareWith(v.First)
+		upMigrations := renamedReferenceMigrations(v.First.CompareWith(v.Second, m.Dialect), v.First, v.Second, renamed)
+		downMigrations := renamedReferenceMigrations(v.Second.CompareWith(v.First, m.Dialect), v.Second, v.First, renamed)
 		schema.SortMigrationsByOperationPriority(upMigrations)
 		schema.SortMigrationsByOperationPriority(downMigrations)
-		m.createColumnMigrations(upMigrations, *v.First, &sbUp)
-		m.createColumnMigrations(downMigrations, *v.Second, &sbDown)
+		if err := m.createTableMigrations(upMigrations, v.First, v.Second, &sbUp); err != nil {
+			return "", "", err
+		}
+		if err := m.createTableMigrations(downMigrations, v.Second, v.First, &sbDown); err != nil {
+			return "", "", err
+		}
 	}
 
 	// Delete created tables in down script
-	for i := len(newTables) - 1; i >= 0; i-- {
-		sbDown.WriteString(m.DropTableQuery(newTables[i]))
+	if err := m.dropTables(newTables, newDeferred, &sbDown); err != nil {
+		return "", "", err
+	}
+
+	if err := m.dropTables(deletedTables, deletedDeferred, &sbUp); err != nil {
+		return "", "", err
+	}
+
+	for _, oldName := range renamedOrder {
+		query, err := m.RenameTableQuery(renamed[oldName], oldName)
+		if err != nil {
+			return "", "", err
+		}
+		sbDown.WriteString(query)
+	}
+
+	return sbUp.String(), sbDown.String(), nil
+
+}
+
+// Writes the queries that create the given dependency sorted tables.
+// Deferred references are added after all of the tables are created
+func (m *Migrator) createTables(tables []*schema.Table, deferred []schema.Reference, sb *strings.Builder) error {
+	// SQLite cannot add foreign keys to existing tables, but it accepts references to tables that are created later.
+	// References that close a cycle are created with their tables
+	if _, ok := m.Dialect.(SQLiteDialect); ok {
+		deferred = nil
+	}
+
+	for _, t := range tables {
+		query, err := m.CreateTableQuery(withoutReferences(t, deferred))
+		if err != nil {
+			return err
+		}
+		sb.WriteString(query)
+	}
+
+	for _, r := range deferred {
+		query, err := m.AddReferenceQuery(r)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(query)
+	}
+	return nil
+}
+
+// Writes the queries that drop the given dependency sorted tables in reverse order.
+// Deferred references are dropped first since they would prevent dropping the tables of the cycle
+func (m *Migrator) dropTables(tables []*schema.Table, deferred []schema.Reference, sb *strings.Builder) error {
+	// SQLite cannot drop foreign keys, the checks are turned off while the tables of a cycle are dropped instead
+	_, sqlite := m.Dialect.(SQLiteDialect)
+	checksOff := sqlite && len(deferred) > 0
+	if checksOff {
+		sb.WriteString(SQLITE_NO_TRANSACTION_NOTE)
+		sb.WriteString("PRAGMA foreign_keys = OFF;\n")
+		deferred = nil
 	}
 
-	for i := len(deletedTables) - 1; i >= 0; i-- {
-		sbUp.WriteString(m.DropTableQuery(deletedTables[i]))
+	for _, r := range deferred {
+		query, err := m.DropReferenceQuery(r)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(query)
 	}
 
-	return sbUp.String(), sbDown.String()
+	for i := len(tables) - 1; i >= 0; i-- {
+		query, err := m.DropTableQuery(tables[i])
+		if err != nil {
+			return err
+		}
+		sb.WriteString(query)
+	}
 
+	if checksOff {
+		sb.WriteString("PRAGMA foreign_keys = ON;\n")
+	}
+	return nil
 }
 
-func (m *Migrator) createColumnMigrations(migrations []*schema.ColumnMigration, table schema.Table, sb *strings.Builder) {
+// Writes the migrations that turn the 'from' table into the 'to' table.
+// If the dialect cannot apply any of them in place, the whole table is rebuilt instead
+func (m *Migrator) createTableMigrations(migrations []*schema.ColumnMigration, from, to *schema.Table, sb *strings.Builder) error {
+	rebuilder, ok := m.Dialect.(TableRebuilder)
+	if !ok || !slices.ContainsFunc(migrations, rebuilder.RequiresRebuild) {
+		return m.createColumnMigrations(migrations, *from, sb)
+	}
+
+	renamed := make(map[string]string)
 	for _, migration := range migrations {
+		if migration.Operation == schema.RENAME_COLUMN {
+			renamed[migration.Old.(schema.Column).Name] = migration.ApplyOn.(schema.Column).Name
+		}
+	}
+
+	query, err := rebuilder.RebuildTableQuery(from, to, renamed)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(query)
+	return nil
+}
+
+func (m *Migrator) createColumnMigrations(migrations []*schema.ColumnMigration, table schema.Table, sb *strings.Builder) error {
+	for _, migration := range migrations {
+		var query string
+		var err error
 		switch migration.Operation {
 		case schema.ADD_COLUMN:
-			sb.WriteString(m.AddColumnQuery(table, migration.ApplyOn.(schema.Column)))
-			break
+			query, err = m.AddColumnQuery(table, migration.ApplyOn.(schema.Column))
 		case schema.DROP_COLUMN:
-			sb.WriteString(m.DropColumnQuery(table, migration.ApplyOn.(schema.Column)))
-			break
+			query, err = m.DropColumnQuery(table, migration.ApplyOn.(schema.Column))
 		case schema.MODIFY_COLUMN:
-			sb.WriteString(m.ModifyColumnQuery(table, migration.ApplyOn.(schema.Column)))
-			break
+			query, err = m.ModifyColumnQuery(table, migration.ApplyOn.(schema.Column), migration.Old.(schema.Column))
 		case schema.RENAME_COLUMN:
-			sb.WriteString(m.RenameColumnQuery(table, migration.ApplyOn.(schema.Column), migration.Old.(schema.Column)))
-			break
+			query, err = m.RenameColumnQuery(table, migration.ApplyOn.(schema.Column), migration.Old.(schema.Column))
 		case schema.ADD_FOREIGN_KEY:
-			sb.WriteString(m.AddReferenceQuery(migration.ApplyOn.(schema.Reference)))
-			break
+			query, err = m.AddReferenceQuery(migration.ApplyOn.(schema.Reference))
 		case schema.DROP_FOREIGN_KEY:
-			sb.WriteString(m.DropReferenceQuery(migration.ApplyOn.(schema.Reference)))
-			break
+			query, err = m.DropReferenceQuery(migration.ApplyOn.(schema.Reference))
 		case schema.UPDATE_FOREIGN_KEY:
-			sb.WriteString(m.DropReferenceQuery(migration.Old.(schema.Reference)))
-			sb.WriteString(m.AddReferenceQuery(migration.ApplyOn.(schema.Reference)))
-			break
+			var add string
+			query, err = m.DropReferenceQuery(migration.Old.(schema.Reference))
+			if err == nil {
+				add, err = m.AddReferenceQuery(migration.ApplyOn.(schema.Reference))
+				query += add
+			}
 		case schema.ADD_UNIQUE_INDEX:
-			sb.WriteString(m.AddUniqueIndexQuery(table, migration.ApplyOn.(string), migration.Old.([]string)))
-			break
+			query, err = m.AddUniqueIndexQuery(table, migration.ApplyOn.(string), migration.Old.([]string))
 		case schema.DROP_UNIQUE_INDEX:
-			sb.WriteString(m.DropUniqueIndexQuery(table, migration.ApplyOn.(string), migration.Old.([]string)))
-			break
+			query, err = m.DropUniqueIndexQuery(table, migration.ApplyOn.(string), migration.Old.([]string))
+		case schema.ADD_INDEX:
+			query, err = m.AddIndexQuery(table, migration.ApplyOn.(schema.Index))
+		case schema.DROP_INDEX:
+			query, err = m.DropIndexQuery(table, migration.ApplyOn.(schema.Index))
+		case schema.ADD_PRIMARY_KEY:
+			query, err = m.AddPrimaryKeyQuery(table, migration.ApplyOn.([]schema.Column))
+		case schema.DROP_PRIMARY_KEY:
+			query, err = m.DropPrimaryKeyQuery(table, migration.ApplyOn.([]schema.Column))
+		case schema.ADD_CHECK:
+			query, err = m.AddCheckQuery(table, migration.ApplyOn.(schema.Check))
+		case schema.DROP_CHECK:
+			query, err = m.DropCheckQuery(table, migration.ApplyOn.(schema.Check))
 		}
+		if err != nil {
+			return err
+		}
+		sb.WriteString(query)
 	}
+	return nil
 }
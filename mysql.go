@@ -0,0 +1,619 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/AkifSahn/migrator/ddl"
+	"github.com/AkifSahn/migrator/schema"
+	"github.com/AkifSahn/migrator/utils"
+	"slices"
+	"strings"
+)
+
+// MySQLDialect generates MySQL compatible migrations.
+// Requires the "github.com/go-sql-driver/mysql" driver, which is registered by this package
+type MySQLDialect struct{}
+
+var _ Dialect = MySQLDialect{}
+
+func (MySQLDialect) Name() string {
+	return "mysql"
+}
+
+func (MySQLDialect) DriverName() string {
+	return "mysql"
+}
+
+func (MySQLDialect) DataType(goType string) (string, error) {
+	return utils.ToMysqlDataType(goType)
+}
+
+// MySQL 8 reports integer types without their display width, except tinyint(1) which booleans are stored as.
+// Aliases are reported as the type they stand for. e.g. integer -> int, numeric -> decimal(10,0)
+func (MySQLDialect) CanonicalType(t schema.ColumnType) schema.ColumnType {
+	switch t.Base {
+	case "bool", "boolean":
+		t.Base, t.Length = "tinyint", 1
+	case "integer":
+		t.Base = "int"
+	case "dec", "numeric", "fixed":
+		t.Base = "decimal"
+	case "double precision", "real":
+		t.Base = "double"
+	case "character varying":
+		t.Base = "varchar"
+	case "character":
+		t.Base = "char"
+	}
+
+	switch t.Base {
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+		if t.Base != "tinyint" || t.Length != 1 {
+			t.Length = -1
+		}
+	case "decimal":
+		if t.Precision == -1 {
+			t.Precision = 10
+		}
+		if t.Scale == -1 {
+			t.Scale = 0
+		}
+	case "float":
+		// float(p) is a double if the precision is larger than 24
+		if t.Precision != -1 && t.Scale == -1 {
+			if t.Precision > 24 {
+				t.Base = "double"
+			}
+			t.Precision = -1
+		}
+	case "char", "binary", "bit":
+		if t.Length == -1 {
+			t.Length = 1
+		}
+	case "datetime", "timestamp", "time":
+		if t.Length == 0 {
+			t.Length = -1
+		}
+	case "year":
+		t.Length = -1
+	}
+
+	if t.Zerofill {
+		t.Unsigned = true
+	}
+	return t
+}
+
+func (MySQLDialect) CreateVersionTableQuery() string {
+	return "CREATE TABLE IF NOT EXISTS `schema_migrations` (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL);\n"
+}
+
+func (MySQLDialect) InsertVersionQuery() string {
+	return "INSERT INTO `schema_migrations` (version, dirty) VALUES (?, ?)"
+}
+
+func (MySQLDialect) SplitStatements(script string) ([]string, error) {
+	return ddl.SplitStatements(script)
+}
+
+func (MySQLDialect) TableNames(ctx context.Context, db *sql.DB, schemaName string) ([]string, error) {
+	query := "SHOW TABLES"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, &IntrospectionError{Query: query, Err: err}
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+
+	return names, nil
+}
+
+func (MySQLDialect) TableComments(ctx context.Context, db *sql.DB, schemaName string) (map[string]string, error) {
+	query := `SELECT TABLE_NAME, TABLE_COMMENT FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = ? AND TABLE_COMMENT != ''`
+	return queryTableComments(ctx, db, query, schemaName)
+}
+
+func (MySQLDialect) DescribeTable(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Column, error) {
+	// Same columns as DESCRIBE, followed by the collation, privileges and comment
+	table := mysqlIdentifier(tableName)
+	if schemaName != "" {
+		table = mysqlIdentifier(schemaName) + "." + table
+	}
+	query := "SHOW FULL COLUMNS FROM " + table
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var cols []*schema.Column
+
+	var key, privileges string
+	var collation sql.NullString
+	for rows.Next() {
+		var col schema.Column
+		col.TableName = tableName
+
+		err := rows.Scan(&col.Name, &col.ColumnType, &collation, &col.Null, &key, &col.DefaultValue, &col.Extra, &privileges, &col.Comment)
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		// Defaults are reported without quotes. Expression defaults are marked as DEFAULT_GENERATED, which is not a column option
+		generated := false
+		var extra []string
+		for _, v := range strings.Fields(col.Extra) {
+			if strings.EqualFold(v, "DEFAULT_GENERATED") {
+				generated = true
+				continue
+			}
+			extra = append(extra, v)
+		}
+		col.Extra = strings.Join(extra, " ")
+		col.DefaultValue = introspectedDefault(col.ColumnType, col.DefaultValue, !generated)
+
+		switch schema.Key(key) {
+		case schema.PRIMARY_KEY:
+			col.PrimaryKey = true
+		case schema.UNIQUE_INDEX:
+			col.UniqueIndex = true
+		}
+
+		cols = append(cols, &col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return cols, nil
+}
+
+func (MySQLDialect) GetPrimaryKey(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]string, error) {
+	query := `SELECT COLUMN_NAME FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+        WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+        ORDER BY ORDINAL_POSITION`
+	return queryPrimaryKey(ctx, db, tableName, query, schemaName, tableName)
+}
+
+func (MySQLDialect) GetReferences(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Reference, error) {
+	query := `SELECT rc.CONSTRAINT_NAME, rc.UPDATE_RULE, rc.DELETE_RULE, rc.TABLE_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME
+        FROM 
+        INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+        JOIN 
+        INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+        ON rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+        AND rc.TABLE_NAME = kcu.TABLE_NAME
+        WHERE 
+        rc.CONSTRAINT_SCHEMA = ? AND kcu.TABLE_SCHEMA = ? AND rc.TABLE_NAME = ? AND kcu.REFERENCED_TABLE_NAME IS NOT NULL`
+
+	rows, err := db.QueryContext(ctx, query, schemaName, schemaName, tableName)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var references []schema.Reference
+	for rows.Next() {
+		var reference schema.Reference
+		err := rows.Scan(&reference.Name, &reference.UpdateOption, &reference.DeleteOption, &reference.TableName, &reference.ColumnName, &reference.ReferencedTableName, &reference.ReferencedColumnName)
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		references = append(references, reference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return references, nil
+}
+
+func (d MySQLDialect) GetUniqueIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) (map[string][]string, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.IndexToUniqueCols, nil
+}
+
+func (d MySQLDialect) GetIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.Indexes, nil
+}
+
+// Check constraints are reported by MySQL 8.0.16 and later, the expressions are rewritten by MySQL. e.g. (`age` > 13)
+func (MySQLDialect) GetChecks(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Check, error) {
+	query := `SELECT tc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+        FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+        JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+        ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+        WHERE tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE = 'CHECK'
+        ORDER BY tc.CONSTRAINT_NAME`
+	return queryChecks(ctx, db, tableName, query, schemaName, tableName)
+}
+
+// Returns every index of the table except the primary key.
+// Foreign keys create an index with the name of the constraint, they are managed by the constraint
+func (MySQLDialect) indexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	query := `SELECT INDEX_NAME, NON_UNIQUE, COLUMN_NAME, COLLATION, SUB_PART, INDEX_TYPE
+        FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME != 'PRIMARY'
+        AND INDEX_NAME NOT IN (
+            SELECT CONSTRAINT_NAME FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS
+            WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_TYPE = 'FOREIGN KEY')
+        ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName, schemaName, tableName)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var indexes []*schema.Index
+	for rows.Next() {
+		var name, columnName, indexType string
+		var nonUnique bool
+		var collation sql.NullString
+		var subPart sql.NullInt64
+		if err := rows.Scan(&name, &nonUnique, &columnName, &collation, &subPart, &indexType); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, &schema.Index{Name: name, Unique: !nonUnique, Type: schema.IndexType(indexType)})
+		}
+		index := indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, schema.IndexColumn{
+			Name:     columnName,
+			Priority: len(index.Columns),
+			Desc:     collation.String == "D",
+			Length:   int(subPart.Int64),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return indexes, nil
+}
+
+func (MySQLDialect) DropTableQuery(t *schema.Table) (string, error) {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", t.Name), nil
+}
+
+func (d MySQLDialect) CreateTableQuery(t *schema.Table) (string, error) {
+	// Definitions are joined at the end, so a table without a primary key, constraints or references has no dangling ','
+	var defs []string
+	for _, c := range t.Columns {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s %s", c.Name, c.ColumnType))
+
+		if c.Null == "NO" {
+			sb.WriteString(" NOT NULL")
+		}
+		if c.Extra != "" {
+			sb.WriteRune(' ')
+			sb.WriteString(c.Extra)
+		}
+		if c.DefaultValue.Valid {
+			sb.WriteRune(' ')
+			sb.WriteString("DEFAULT ")
+			sb.WriteString(c.DefaultValue.String)
+		}
+		if c.Comment != "" {
+			sb.WriteString(" COMMENT " + mysqlString(c.Comment))
+		}
+		defs = append(defs, sb.String())
+	}
+
+	if len(t.PrimaryCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(t.PrimaryCols, ", ")))
+	}
+
+	for _, iName := range t.UniqueIndexNames() {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT `%s` UNIQUE (%s)", iName, strings.Join(t.IndexToUniqueCols[iName], ", ")))
+	}
+
+	for _, reference := range t.References {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES %s(%s) ON DELETE %s ON UPDATE %s",
+			reference.Name, reference.ColumnName,
+			reference.ReferencedTableName, reference.ReferencedColumnName, reference.DeleteOption, reference.UpdateOption))
+	}
+
+	for _, check := range t.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT `%s` CHECK (%s)", check.Name, check.Expression))
+	}
+
+	if len(defs) == 0 {
+		return "", &UnsupportedOperationError{Operation: "CREATE TABLE", Table: t.Name, Reason: "table has no columns"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);\n", t.Name, strings.Join(defs, ",\n\t")))
+	for _, index := range t.Indexes {
+		query, err := d.AddIndexQuery(*t, *index)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(query)
+	}
+	return sb.String(), nil
+}
+
+func (MySQLDialect) AddColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", t.Name))
+	sb.WriteString(fmt.Sprintf("ADD COLUMN %s %s", c.Name, strings.ToUpper(c.ColumnType)))
+
+	if c.Null == "NO" {
+		sb.WriteString(" NOT NULL")
+	}
+	// Check if column has any extras. auto_increment etc.
+	// Primary key is added after the column, so auto_increment is set by 'AddPrimaryKeyQuery'
+	extra := c.Extra
+	if c.PrimaryKey {
+		extra = withoutAutoIncrement(extra)
+	}
+	if extra != "" {
+		sb.WriteRune(' ')
+		sb.WriteString(extra)
+	}
+
+	if c.DefaultValue.Valid {
+		sb.WriteRune(' ')
+		sb.WriteString("DEFAULT ")
+		sb.WriteString(c.DefaultValue.String)
+	}
+	if c.Comment != "" {
+		sb.WriteString(" COMMENT " + mysqlString(c.Comment))
+	}
+
+	sb.WriteString(";\n")
+	return sb.String(), nil
+}
+
+func (MySQLDialect) DropColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", t.Name))
+	sb.WriteString(fmt.Sprintf("DROP COLUMN %s", c.Name))
+
+	sb.WriteString(";\n")
+	return sb.String(), nil
+}
+
+func (MySQLDialect) ModifyColumnQuery(t schema.Table, c schema.Column, old schema.Column) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", t.Name))
+	sb.WriteString(fmt.Sprintf("MODIFY COLUMN %s %s", c.Name, strings.ToUpper(c.ColumnType)))
+
+	if c.Null == "NO" {
+		sb.WriteString(" NOT NULL")
+	}
+
+	if c.Extra != "" {
+		sb.WriteRune(' ')
+		sb.WriteString(c.Extra)
+	}
+
+	if c.DefaultValue.Valid {
+		sb.WriteRune(' ')
+		sb.WriteString("DEFAULT ")
+		sb.WriteString(c.DefaultValue.String)
+	}
+	if c.Comment != "" {
+		sb.WriteString(" COMMENT " + mysqlString(c.Comment))
+	}
+
+	sb.WriteString(";\n")
+
+	return sb.String(), nil
+}
+
+func (MySQLDialect) RenameColumnQuery(t schema.Table, newCol schema.Column, oldColumn schema.Column) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", t.Name))
+	sb.WriteString(fmt.Sprintf("RENAME COLUMN %s TO %s;\n", oldColumn.Name, newCol.Name))
+	return sb.String(), nil
+}
+
+func (MySQLDialect) RenameTableQuery(oldName, newName string) (string, error) {
+	return fmt.Sprintf("RENAME TABLE %s TO %s;\n", oldName, newName), nil
+}
+
+func (MySQLDialect) AddReferenceQuery(reference schema.Reference) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", reference.TableName))
+	sb.WriteString(fmt.Sprintf("ADD CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES %s(%s) ON DELETE %s ON UPDATE %s;\n",
+		reference.Name, reference.ColumnName, reference.ReferencedTableName,
+		reference.ReferencedColumnName, reference.DeleteOption, reference.UpdateOption))
+
+	return sb.String(), nil
+}
+
+func (MySQLDialect) DropReferenceQuery(reference schema.Reference) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", reference.TableName))
+	sb.WriteString(fmt.Sprintf("DROP CONSTRAINT `%s`,\n\t", reference.Name))
+	sb.WriteString(fmt.Sprintf("DROP INDEX `%s`;\n", reference.Name))
+
+	return sb.String(), nil
+}
+
+func (MySQLDialect) AddUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", table.Name))
+	sb.WriteString(fmt.Sprintf("ADD CONSTRAINT `%s` UNIQUE (", indexName))
+	for i, col := range colNames {
+		sb.WriteString(fmt.Sprintf("%s", col))
+		if i < len(colNames)-1 {
+			sb.WriteString(", ")
+		}
+	}
+	sb.WriteString(");\n")
+
+	return sb.String(), nil
+}
+
+func (d MySQLDialect) DropUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	var sb strings.Builder
+
+	// If unique index is required in a foreign key, drop the foreign key first.
+	// Then drop the unique constraint, then add back foreign key
+
+	if len(colNames) > 0 {
+		sb.WriteString("\n-- Removing unique constraint from a foreign key requires dropping and then adding back the foreign key!\n")
+	}
+	for _, colName := range colNames {
+		referenceIndex := slices.IndexFunc(table.References, func(r schema.Reference) bool { return r.ColumnName == colName })
+		if referenceIndex != -1 {
+			query, err := d.DropReferenceQuery(table.References[referenceIndex])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(query)
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", table.Name))
+	sb.WriteString(fmt.Sprintf("DROP CONSTRAINT `%s`;\n", indexName))
+
+	for _, colName := range colNames {
+		referenceIndex := slices.IndexFunc(table.References, func(r schema.Reference) bool { return r.ColumnName == colName })
+		if referenceIndex != -1 {
+			query, err := d.AddReferenceQuery(table.References[referenceIndex])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(query)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (MySQLDialect) AddIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("CREATE ")
+	switch {
+	case index.Type == schema.FULLTEXT_INDEX || index.Type == schema.SPATIAL_INDEX:
+		sb.WriteString(fmt.Sprintf("%s ", index.Type))
+	case index.Unique:
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString(fmt.Sprintf("INDEX `%s` ON %s (", index.Name, table.Name))
+	for i, col := range index.Columns {
+		sb.WriteString(col.Name)
+		if col.Length > 0 {
+			sb.WriteString(fmt.Sprintf("(%d)", col.Length))
+		}
+		if col.Desc {
+			sb.WriteString(" DESC")
+		}
+		if i < len(index.Columns)-1 {
+			sb.WriteString(", ")
+		}
+	}
+	sb.WriteRune(')')
+	if index.Type == schema.HASH_INDEX {
+		sb.WriteString(" USING HASH")
+	}
+	sb.WriteString(";\n")
+
+	return sb.String(), nil
+}
+
+func (MySQLDialect) DropIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	return fmt.Sprintf("DROP INDEX `%s` ON %s;\n", index.Name, table.Name), nil
+}
+
+func (MySQLDialect) AddCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD CONSTRAINT `%s` CHECK (%s);\n", table.Name, check.Name, check.Expression), nil
+}
+
+func (MySQLDialect) DropCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP CHECK `%s`;\n", table.Name, check.Name), nil
+}
+
+// MySQL requires the auto_increment column to be a key, so auto_increment is removed while the primary key is dropped
+func (MySQLDialect) DropPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", table.Name))
+	for _, c := range cols {
+		if extra := withoutAutoIncrement(c.Extra); extra != c.Extra {
+			sb.WriteString(fmt.Sprintf("MODIFY COLUMN %s,\n\t", primaryKeyColumnDefinition(c, extra)))
+		}
+	}
+	sb.WriteString("DROP PRIMARY KEY;\n")
+	return sb.String(), nil
+}
+
+// auto_increment of the key columns is set again with the new primary key
+func (MySQLDialect) AddPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	var names []string
+	var modified []string
+	for _, c := range cols {
+		names = append(names, c.Name)
+		if withoutAutoIncrement(c.Extra) != c.Extra {
+			modified = append(modified, fmt.Sprintf(",\n\tMODIFY COLUMN %s", primaryKeyColumnDefinition(c, c.Extra)))
+		}
+	}
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD PRIMARY KEY (%s)%s;\n", table.Name, strings.Join(names, ", "), strings.Join(modified, "")), nil
+}
+
+// Returns the definition of a primary key column with the given extra
+func primaryKeyColumnDefinition(c schema.Column, extra string) string {
+	definition := fmt.Sprintf("%s %s NOT NULL", c.Name, strings.ToUpper(c.ColumnType))
+	if extra != "" {
+		definition += " " + extra
+	}
+	if c.DefaultValue.Valid {
+		definition += " DEFAULT " + c.DefaultValue.String
+	}
+	if c.Comment != "" {
+		definition += " COMMENT " + mysqlString(c.Comment)
+	}
+	return definition
+}
+
+// Returns the column extra without auto_increment. e.g. "auto_increment" -> ""
+func withoutAutoIncrement(extra string) string {
+	var kept []string
+	for _, v := range strings.Fields(extra) {
+		if !strings.EqualFold(v, "auto_increment") {
+			kept = append(kept, v)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// Returns the name as a backtick quoted MySQL identifier, backticks in the name are doubled
+func mysqlIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Returns the value as a MySQL string literal. Backslashes are escaped as well, since they are escape characters by default
+func mysqlString(value string) string {
+	return quoteString(strings.ReplaceAll(value, `\`, `\\`))
+}
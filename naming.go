@@ -0,0 +1,285 @@
+package migrator
+
+import (
+	"cmp"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/AkifSahn/migrator/utils"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tabler is implemented by the models that declare their own table name, same as gorm
+type Tabler interface {
+	TableName() string
+}
+
+// NamingStrategy decides the names of the tables, columns and constraints created for the models
+type NamingStrategy interface {
+	// Returns the table name of the given struct name
+	TableName(str string) string
+	// Returns the column name of the given field name
+	ColumnName(table, column string) string
+	// Returns the name of the join table of a many to many relation
+	JoinTableName(joinTable string) string
+	// Returns the name of the foreign key constraint created for the relation field of the given table
+	RelationshipFKName(table, relation string) string
+	// Returns the name of the index on the given field
+	IndexName(table, column string) string
+	// Returns the name of the unique constraint on the given field
+	UniqueName(table, column string) string
+	// Returns the name of the check constraint declared on the given field
+	CheckName(table, column string) string
+}
+
+// KeyNamer is implemented by the naming strategies that name foreign keys and unique indexes by the columns
+// they are on instead of the relation field, like LegacyNamingStrategy
+type KeyNamer interface {
+	// Returns the name of the foreign key constraint on the given column
+	ForeignKeyName(table, column string) string
+	// Returns the name of the unique index declared by a 'uniqueIndex' tag. 'name' is the name in the tag, empty if it has none
+	UniqueIndexName(table, column, name string) string
+}
+
+// Replacer replaces the struct and field names before they are converted into database names
+type Replacer interface {
+	Replace(name string) string
+}
+
+// DefaultNamingStrategy generates the same names as gorm's default 'schema.NamingStrategy',
+// so the tables and constraints match the ones gorm queries at runtime
+type DefaultNamingStrategy struct {
+	TablePrefix         string
+	SingularTable       bool
+	NameReplacer        Replacer
+	NoLowerCase         bool
+	IdentifierMaxLength int // 64 if not set
+}
+
+var _ NamingStrategy = DefaultNamingStrategy{}
+
+func (ns DefaultNamingStrategy) TableName(str string) string {
+	if ns.SingularTable {
+		return ns.TablePrefix + ns.toDBName(str)
+	}
+	return ns.TablePrefix + utils.Plural(ns.toDBName(str))
+}
+
+func (ns DefaultNamingStrategy) ColumnName(table, column string) string {
+	return ns.toDBName(column)
+}
+
+func (ns DefaultNamingStrategy) JoinTableName(str string) string {
+	if !ns.NoLowerCase && strings.ToLower(str) == str {
+		return ns.TablePrefix + str
+	}
+	return ns.TableName(str)
+}
+
+func (ns DefaultNamingStrategy) RelationshipFKName(table, relation string) string {
+	return ns.formatName("fk", table, ns.toDBName(relation))
+}
+
+func (ns DefaultNamingStrategy) IndexName(table, column string) string {
+	return ns.formatName("idx", table, ns.toDBName(column))
+}
+
+func (ns DefaultNamingStrategy) UniqueName(table, column string) string {
+	return ns.formatName("uni", table, ns.toDBName(column))
+}
+
+func (ns DefaultNamingStrategy) CheckName(table, column string) string {
+	return ns.formatName("chk", table, ns.toDBName(column))
+}
+
+// Joins the parts with '_'. Names longer than the identifier limit are cut and suffixed with their hash
+func (ns DefaultNamingStrategy) formatName(prefix, table, name string) string {
+	formattedName := strings.ReplaceAll(strings.Join([]string{prefix, table, name}, "_"), ".", "_")
+
+	maxLength := ns.IdentifierMaxLength
+	if maxLength == 0 {
+		maxLength = 64
+	}
+	if utf8.RuneCountInString(formattedName) > maxLength {
+		hash := sha1.Sum([]byte(formattedName))
+		formattedName = formattedName[0:maxLength-8] + hex.EncodeToString(hash[:])[:8]
+	}
+	return formattedName
+}
+
+// Initialisms are kept together when converting to snake case. e.g. "HTTPStatus" -> "http_status"
+var commonInitialisms = []string{"API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SSH", "TLS", "TTL", "UID", "UI", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XSRF", "XSS"}
+
+var commonInitialismsReplacer = newInitialismsReplacer()
+
+func newInitialismsReplacer() *strings.Replacer {
+	var oldnew []string
+	for _, initialism := range commonInitialisms {
+		oldnew = append(oldnew, initialism, initialism[:1]+strings.ToLower(initialism[1:]))
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// Converts the given go name into snake case
+func (ns DefaultNamingStrategy) toDBName(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	if ns.NameReplacer != nil {
+		if replaced := ns.NameReplacer.Replace(name); replaced != "" {
+			name = replaced
+		}
+	}
+
+	if ns.NoLowerCase {
+		return name
+	}
+
+	var (
+		value                          = commonInitialismsReplacer.Replace(name)
+		sb                             strings.Builder
+		lastCase, nextCase, nextNumber bool // upper case == true
+		curCase                        = value[0] <= 'Z' && value[0] >= 'A'
+	)
+
+	for i, v := range value[:len(value)-1] {
+		nextCase = value[i+1] <= 'Z' && value[i+1] >= 'A'
+		nextNumber = value[i+1] >= '0' && value[i+1] <= '9'
+
+		if curCase {
+			if lastCase && (nextCase || nextNumber) {
+				sb.WriteRune(v + 32)
+			} else {
+				if i > 0 && value[i-1] != '_' && value[i+1] != '_' {
+					sb.WriteByte('_')
+				}
+				sb.WriteRune(v + 32)
+			}
+		} else {
+			sb.WriteRune(v)
+		}
+
+		lastCase = curCase
+		curCase = nextCase
+	}
+
+	if curCase {
+		if !lastCase && len(value) > 1 {
+			sb.WriteByte('_')
+		}
+		sb.WriteByte(value[len(value)-1] + 32)
+	} else {
+		sb.WriteByte(value[len(value)-1])
+	}
+	return sb.String()
+}
+
+// LegacyNamingStrategy generates the names of the migrator versions before NamingStrategy was added.
+// e.g. 'uc.users.email' for unique keys and 'fk.posts.user_id' for foreign keys.
+// Set it as 'Migrator.Naming' to keep migrating the schemas that were created with those names
+type LegacyNamingStrategy struct{}
+
+var (
+	_ NamingStrategy = LegacyNamingStrategy{}
+	_ KeyNamer       = LegacyNamingStrategy{}
+)
+
+func (LegacyNamingStrategy) TableName(str string) string {
+	return legacyPlural(legacyDBName(str))
+}
+
+func (LegacyNamingStrategy) ColumnName(table, column string) string {
+	return legacyDBName(column)
+}
+
+func (ns LegacyNamingStrategy) JoinTableName(str string) string {
+	if strings.ToLower(str) == str {
+		return str
+	}
+	return ns.TableName(str)
+}
+
+func (LegacyNamingStrategy) RelationshipFKName(table, relation string) string {
+	return "fk." + table + "." + legacyDBName(relation)
+}
+
+func (LegacyNamingStrategy) ForeignKeyName(table, column string) string {
+	return "fk." + table + "." + column
+}
+
+func (LegacyNamingStrategy) IndexName(table, column string) string {
+	return "idx." + table + "." + legacyDBName(column)
+}
+
+func (LegacyNamingStrategy) UniqueName(table, column string) string {
+	return "uc." + table + "." + legacyDBName(column)
+}
+
+func (LegacyNamingStrategy) UniqueIndexName(table, column, name string) string {
+	return "uc." + table + "." + cmp.Or(name, column)
+}
+
+func (LegacyNamingStrategy) CheckName(table, column string) string {
+	return "chk." + table + "." + legacyDBName(column)
+}
+
+var legacyWordRegexp = regexp.MustCompile("([a-z])([A-Z])")
+
+// Converts the given go name into lower snake case, only splitting a lower case letter followed by an upper case one.
+// e.g. "UserID" -> "user_id", "HTTPStatus" -> "httpstatus"
+func legacyDBName(name string) string {
+	return strings.ToLower(legacyWordRegexp.ReplaceAllString(name, "${1}_${2}"))
+}
+
+// Returns the plural of the given word by the suffix rules of the legacy naming. Words ending with 's' are kept
+func legacyPlural(word string) string {
+	switch {
+	case strings.HasSuffix(word, "s"):
+		return word
+	case len(word) > 1 && strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "o") || strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "fe"):
+		return word[:len(word)-2] + "ves"
+	case strings.HasSuffix(word, "f"):
+		return word[:len(word)-1] + "ves"
+	}
+	return word + "s"
+}
+
+// Returns the naming strategy of the migrator, gorm's default naming if it is not set
+func (m *Migrator) namer() NamingStrategy {
+	if m.Naming == nil {
+		return DefaultNamingStrategy{}
+	}
+	return m.Naming
+}
+
+// Returns the name of the foreign key constraint of the relation field on the column of the foreign key table
+func (m *Migrator) foreignKeyName(table, relation, fkTable, column string) string {
+	if namer, ok := m.namer().(KeyNamer); ok {
+		return namer.ForeignKeyName(fkTable, column)
+	}
+	return m.namer().RelationshipFKName(table, relation)
+}
+
+// Returns the name of the unique index of a 'uniqueIndex' tag on the given field. 'name' is the name in the tag, empty if it has none
+func (m *Migrator) uniqueIndexName(table, fieldName, column, name string) string {
+	if namer, ok := m.namer().(KeyNamer); ok {
+		return namer.UniqueIndexName(table, column, name)
+	}
+	return cmp.Or(name, m.namer().IndexName(table, fieldName))
+}
+
+// Returns the table name of the given model type.
+// Uses the 'TableName' method if the model implements 'Tabler'
+func (m *Migrator) tableName(typ reflect.Type) string {
+	if tabler, ok := reflect.New(typ).Interface().(Tabler); ok {
+		return tabler.TableName()
+	}
+	return m.namer().TableName(typ.Name())
+}
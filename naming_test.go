@@ -0,0 +1,101 @@
+package migrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type legacyCompany struct {
+	ID        uint             `gorm:"primaryKey"`
+	Name      string           `gorm:"uniqueIndex"`
+	Employees []legacyEmployee `gorm:"foreignKey:CompanyID"`
+}
+
+type legacyEmployee struct {
+	ID        uint `gorm:"primaryKey"`
+	CompanyID uint
+	Badge     legacyBadge `gorm:"foreignKey:EmployeeID"`
+}
+
+type legacyBadge struct {
+	ID         uint `gorm:"primaryKey"`
+	EmployeeID uint
+	Code       string `gorm:"type:varchar(20);uniqueIndex:code"`
+}
+
+func TestLegacyNamingStrategy(t *testing.T) {
+	ns := LegacyNamingStrategy{}
+	tests := []struct {
+		got, want string
+	}{
+		{ns.TableName("Company"), "companies"},
+		{ns.TableName("UserStatus"), "user_status"},
+		{ns.TableName("Hero"), "heroes"},
+		{ns.ColumnName("users", "UserID"), "user_id"},
+		{ns.ColumnName("users", "HTTPStatus"), "httpstatus"},
+		{ns.UniqueName("users", "Email"), "uc.users.email"},
+		{ns.UniqueIndexName("users", "email", ""), "uc.users.email"},
+		{ns.UniqueIndexName("users", "email", "login"), "uc.users.login"},
+		{ns.ForeignKeyName("posts", "user_id"), "fk.posts.user_id"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("legacy name is %q, want %q", test.got, test.want)
+		}
+	}
+}
+
+// Schema created by the migrator before NamingStrategy was added is not changed by the legacy naming
+func TestLegacySchemaIsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	legacy := Migration{Version: 1, Name: "init", Up: "CREATE TABLE legacy_companies (\n" +
+		"\tid bigint unsigned NOT NULL,\n" +
+		"\tname varchar(255),\n" +
+		"\tPRIMARY KEY (id),\n" +
+		"\tCONSTRAINT `uc.legacy_companies.name` UNIQUE (name)\n" +
+		");\n" +
+		"CREATE TABLE legacy_employees (\n" +
+		"\tid bigint unsigned NOT NULL,\n" +
+		"\tcompany_id bigint unsigned,\n" +
+		"\tPRIMARY KEY (id),\n" +
+		"\tCONSTRAINT `fk.legacy_employees.company_id` FOREIGN KEY (company_id) REFERENCES legacy_companies(id) ON DELETE CASCADE ON UPDATE CASCADE\n" +
+		");\n" +
+		"CREATE TABLE legacy_badges (\n" +
+		"\tid bigint unsigned NOT NULL,\n" +
+		"\temployee_id bigint unsigned,\n" +
+		"\tcode varchar(20),\n" +
+		"\tPRIMARY KEY (id),\n" +
+		"\tCONSTRAINT `uc.legacy_badges.code` UNIQUE (code),\n" +
+		"\tCONSTRAINT `uc.legacy_badges.employee_id` UNIQUE (employee_id),\n" +
+		"\tCONSTRAINT `fk.legacy_badges.employee_id` FOREIGN KEY (employee_id) REFERENCES legacy_employees(id) ON DELETE CASCADE ON UPDATE CASCADE\n" +
+		");\n"}
+	if _, err := (GolangMigrateWriter{}).WriteMigration(dir, legacy, false); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewOfflineMigrator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	m.Naming = LegacyNamingStrategy{}
+	up, down, err := m.createMigrationScripts(ctx, dir, legacyCompany{}, legacyEmployee{}, legacyBadge{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(up) != "" || strings.TrimSpace(down) != "" {
+		t.Errorf("legacy schema is migrated with the legacy naming:\n%s\n%s", up, down)
+	}
+
+	// GORM naming renames the constraints
+	m.Naming = nil
+	up, _, err = m.createMigrationScripts(ctx, dir, legacyCompany{}, legacyEmployee{}, legacyBadge{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(up, "idx_legacy_companies_name") {
+		t.Errorf("legacy constraints are kept with the default naming:\n%s", up)
+	}
+}
@@ -0,0 +1,73 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/AkifSahn/migrator/ddl"
+	"github.com/AkifSahn/migrator/schema"
+	"os"
+)
+
+// Returns a MySQL migrator instance that doesn't connect to a database.
+// Current state of the database is built by replaying the up scripts in the given migrations directory
+func NewOfflineMigrator(migrationsDir string) (*Migrator, error) {
+	m := &Migrator{
+		Dialect:       MySQLDialect{},
+		MigrationsDir: migrationsDir,
+		Relations:     make([]schema.Reference, 0),
+	}
+
+	version, err := m.getOfflineVersion(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	m.CurrentVersion = version
+	return m, nil
+}
+
+// Returns true if the migrator has no database connection
+func (m *Migrator) IsOffline() bool {
+	return m.DB == nil
+}
+
+// Returns the version of the latest migration file of the directory. Missing directory means no migrations
+func (m *Migrator) getOfflineVersion(dir string) (int64, error) {
+	migrations, err := m.readSavedMigrations(dir)
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Returns the migrations of the directory in the format of the writer. Missing directory means no migrations
+func (m *Migrator) readSavedMigrations(dir string) ([]Migration, error) {
+	migrations, err := m.writer().ReadMigrations(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return migrations, err
+}
+
+// Builds the tables by replaying the up scripts of the migrations directory in order
+func (m *Migrator) replayMigrations(dir string) ([]*schema.Table, error) {
+	if _, ok := m.Dialect.(MySQLDialect); !ok {
+		return nil, fmt.Errorf("replaying migrations is only supported for mysql, not %s", m.Dialect.Name())
+	}
+
+	migrations, err := m.readSavedMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	state := ddl.NewState()
+	for _, migration := range migrations {
+		if err := state.Apply(migration.Up); err != nil {
+			return nil, fmt.Errorf("replaying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return state.Tables(), nil
+}
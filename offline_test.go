@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+	"testing"
+)
+
+func TestReplayMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, migration := range []Migration{
+		{Version: 1, Name: "init", Up: `CREATE TABLE users (
+	id bigint NOT NULL AUTO_INCREMENT,
+	email varchar(255) NOT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT uni_users_email UNIQUE (email)
+);
+CREATE TABLE posts (
+	id bigint NOT NULL,
+	user_id bigint,
+	title text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_posts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE TABLE logs (id int);
+`},
+		{Version: 2, Name: "alter", Up: `ALTER TABLE users ADD COLUMN name varchar(100) DEFAULT 'a;b';
+ALTER TABLE posts RENAME COLUMN title TO subject; -- ;
+CREATE INDEX idx_posts_subject ON posts (subject(10));
+DROP TABLE logs;
+`},
+		{Version: 3, Name: "drop_email", Up: "ALTER TABLE users DROP COLUMN email;\n"},
+	} {
+		if _, err := (GolangMigrateWriter{}).WriteMigration(dir, migration, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewOfflineMigrator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.CurrentVersion != 3 {
+		t.Errorf("current version is %d, want 3", m.CurrentVersion)
+	}
+
+	tables, err := m.replayMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0].Name != "posts" || tables[1].Name != "users" {
+		t.Fatalf("replayed tables %v, want posts and users", tableNames(tables))
+	}
+	posts, users := tables[0], tables[1]
+
+	if got := columnNames(users); !slices.Equal(got, []string{"id", "name"}) {
+		t.Errorf("users columns %v, want [id name]", got)
+	}
+	if name := users.GetColumn("name"); name == nil || name.DefaultValue.String != "'a;b'" {
+		t.Errorf("users.name is %+v, want default 'a;b'", name)
+	}
+	if users.GetColumn("id").Extra != "auto_increment" || !slices.Equal(users.PrimaryCols, []string{"id"}) {
+		t.Errorf("users.id is %+v with primary key %v", users.GetColumn("id"), users.PrimaryCols)
+	}
+	if len(users.IndexToUniqueCols) != 0 {
+		t.Errorf("unique constraints %v are left after dropping their column", users.IndexToUniqueCols)
+	}
+
+	if got := columnNames(posts); !slices.Equal(got, []string{"id", "user_id", "subject"}) {
+		t.Errorf("posts columns %v, want [id user_id subject]", got)
+	}
+	if len(posts.References) != 1 || posts.References[0].Name != "fk_posts_user" ||
+		posts.References[0].ReferencedTableName != "users" || posts.References[0].DeleteOption != schema.CASCADE_OPTION {
+
+		t.Errorf("posts references %+v", posts.References)
+	}
+	if index := posts.GetIndex("idx_posts_subject"); index == nil ||
+		!slices.Equal(index.Columns, []schema.IndexColumn{{Name: "subject", Length: 10}}) {
+
+		t.Errorf("posts index is %+v", index)
+	}
+
+	// Tables dropped by an earlier migration can't be dropped again
+	if _, err := (GolangMigrateWriter{}).WriteMigration(dir, Migration{Version: 4, Name: "drop_logs", Up: "DROP TABLE logs;\n"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.replayMigrations(dir); err == nil {
+		t.Error("dropping a dropped table is replayed without an error")
+	}
+
+	// Offline migrators have no database to introspect
+	if _, err := m.DescribeTable("users"); err == nil {
+		t.Error("offline migrator describes a table without an error")
+	}
+	if _, err := m.GetReferences("posts"); err == nil {
+		t.Error("offline migrator reads references without an error")
+	}
+}
+
+func tableNames(tables []*schema.Table) []string {
+	var names []string
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func columnNames(t *schema.Table) []string {
+	var names []string
+	for _, c := range t.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -0,0 +1,171 @@
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Options of 'Migrator.MigrateWithOptions'
+type Options struct {
+	Name       string    // Name of the migration, turned into a file name. e.g. "Add users table" -> "add_users_table"
+	Dir        string    // Directory the migration files are saved in, created if it doesn't exist
+	DryRun     bool      // Writes the migration scripts into Output instead of saving them
+	Output     io.Writer // Progress messages and dry run scripts are written into Output, os.Stdout if nil
+	Versioning Versioning
+	Overwrite  bool     // Replaces the files of an existing migration with the same version and name instead of failing
+	AllowDrops []string // Tables and columns whose destructive changes are allowed in this migration, added to 'Migrator.AllowDrops'
+
+	// DSN of an empty, throwaway database of the same dialect. If it is set, the migration is applied on it before it is saved,
+	// together with the saved migrations, and the resulting schema is checked. Its tables are dropped afterwards
+	ShadowDSN    string
+	ShadowSchema string // Schema name of the shadow database, 'Migrator.SchemaName' if empty
+}
+
+// Creates the migration scripts of the given target models and saves them into 'opts.Dir' by 'Migrator.Writer',
+// e.g. "<version>_<name>.[up/down].sql". Unlike 'MigrateAndSave' it never reads from stdin.
+// Returns the paths of the saved files, nil if no migration is necessary or 'opts.DryRun' is set
+func (m *Migrator) MigrateWithOptions(ctx context.Context, opts Options, targetModels ...interface{}) ([]string, error) {
+	name := slugify(opts.Name)
+	if !opts.DryRun && name == "" {
+		return nil, fmt.Errorf("invalid migration name %q", opts.Name)
+	}
+	return m.migrateWithOptions(ctx, opts, func(io.Writer) (string, error) { return name, nil }, targetModels...)
+}
+
+// Creates the migration scripts and saves them by the options. Name of the migration is asked from migrationName
+// once the migration is known to be necessary, it is given the output of the options
+func (m *Migrator) migrateWithOptions(ctx context.Context, opts Options, migrationName func(out io.Writer) (string, error), targetModels ...interface{}) ([]string, error) {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	if len(opts.AllowDrops) > 0 {
+		allowDrops := m.AllowDrops
+		m.AllowDrops = append(slices.Clone(allowDrops), opts.AllowDrops...)
+		defer func() { m.AllowDrops = allowDrops }()
+	}
+
+	upScript, downScript, err := m.createMigrationScripts(ctx, opts.Dir, targetModels...)
+	if err != nil {
+		return nil, err
+	}
+
+	if upScript == "" {
+		fmt.Fprintln(out, "No migration necessary!")
+		return nil, nil
+	}
+
+	if opts.ShadowDSN != "" {
+		if err := m.verifyMigration(ctx, opts, upScript, downScript, targetModels...); err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(out, "Migration is verified on the shadow database")
+	}
+
+	if opts.DryRun {
+		printScripts(out, upScript, downScript)
+		return nil, nil
+	}
+
+	name, err := migrationName(out)
+	if err != nil {
+		return nil, err
+	}
+	return m.saveMigration(out, opts, name, upScript, downScript)
+}
+
+// Returns the up and down scripts that bring the current database state to the given target models.
+// Offline migrators replay the migrations of the given directory, or of 'MigrationsDir' if it is empty
+func (m *Migrator) createMigrationScripts(ctx context.Context, dir string, targetModels ...interface{}) (string, string, error) {
+	dir = cmp.Or(dir, m.MigrationsDir)
+	if m.IsOffline() {
+		version, err := m.getOfflineVersion(dir)
+		if err != nil {
+			return "", "", err
+		}
+		m.CurrentVersion = version
+	}
+
+	// Desired database state
+	dst, err := m.ParseTablesFromStructs(targetModels...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return m.createMigration(ctx, dir, dst)
+}
+
+// Writes the migration scripts into the directory of the options as the next version of the versioning.
+// Directory is created if it doesn't exist. Returns the paths of the written files
+func (m *Migrator) saveMigration(out io.Writer, opts Options, name, upScript, downScript string) ([]string, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("migration directory is required")
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating migration directory: %w", err)
+	}
+
+	writer := m.writer()
+	migrations, err := writer.ReadMigrations(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	versions := migrationVersions(migrations)
+	version, err := m.nextVersion(opts.Versioning, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skipped versions don't stop the migration, they are only reported
+	if opts.Versioning != TIMESTAMP_VERSIONING {
+		if gaps := versionGaps(append(versions, version)); len(gaps) > 0 {
+			fmt.Fprintln(out, "Warning:", &VersionError{Dir: opts.Dir, Gaps: gaps})
+		}
+	}
+
+	paths, err := writer.WriteMigration(opts.Dir, Migration{Version: version, Name: name, Up: upScript, Down: downScript}, opts.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := consumeAllowedDrops(opts.Dir); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "Migration %d_%s is saved as: %s\n", version, name, strings.Join(paths, ", "))
+	return paths, nil
+}
+
+func printScripts(out io.Writer, upScript, downScript string) {
+	fmt.Fprintln(out, "*****UP SCRIPT*****")
+	fmt.Fprintln(out, upScript)
+	fmt.Fprintln(out, "*****DOWN SCRIPT*****")
+	fmt.Fprintln(out, downScript)
+	fmt.Fprintln(out, "--dry-run argument is passed, no migration file created!")
+}
+
+// Turns the given name into a file name friendly slug.
+// Letters are lower cased, every other run of characters is replaced by a single '_'. e.g. "Add users-table" -> "add_users_table"
+func slugify(name string) string {
+	var sb strings.Builder
+	separate := false
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = sb.Len() > 0
+			continue
+		}
+		if separate {
+			sb.WriteByte('_')
+			separate = false
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
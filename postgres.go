@@ -0,0 +1,558 @@
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/AkifSahn/migrator/ddl"
+	"github.com/AkifSahn/migrator/schema"
+	"github.com/AkifSahn/migrator/utils"
+	"strings"
+)
+
+// PostgresDialect generates PostgreSQL compatible migrations.
+// Auto increment columns are created as identity columns, existing serial columns are recognised as auto increment.
+// The driver must be imported by the caller, "github.com/lib/pq" is used unless Driver is set. e.g. "pgx"
+type PostgresDialect struct {
+	Driver string
+}
+
+var (
+	_ Dialect         = PostgresDialect{}
+	_ PrimaryKeyNamer = PostgresDialect{}
+)
+
+func (PostgresDialect) Name() string {
+	return "postgres"
+}
+
+func (d PostgresDialect) DriverName() string {
+	if d.Driver != "" {
+		return d.Driver
+	}
+	return "postgres"
+}
+
+func (PostgresDialect) DataType(goType string) (string, error) {
+	return utils.ToPostgresDataType(goType)
+}
+
+// Aliases are reported by their SQL standard names. e.g. int -> integer, varchar -> character varying, timestamptz -> timestamp with time zone.
+// Serial types are integers with a sequence default, the default is introspected separately
+func (PostgresDialect) CanonicalType(t schema.ColumnType) schema.ColumnType {
+	switch t.Base {
+	case "int", "int4", "serial", "serial4":
+		t.Base = "integer"
+	case "int8", "bigserial", "serial8":
+		t.Base = "bigint"
+	case "int2", "smallserial", "serial2":
+		t.Base = "smallint"
+	case "bool":
+		t.Base = "boolean"
+	case "varchar":
+		t.Base = "character varying"
+	case "char", "bpchar":
+		t.Base = "character"
+	case "varbit":
+		t.Base = "bit varying"
+	case "decimal":
+		t.Base = "numeric"
+	case "float8", "double":
+		t.Base = "double precision"
+	case "float4":
+		t.Base = "real"
+	case "float":
+		// float(p) is a real if the precision is at most 24
+		t.Base = "double precision"
+		if t.Precision != -1 && t.Precision <= 24 {
+			t.Base = "real"
+		}
+		t.Precision = -1
+	case "timestamptz":
+		t.Base = "timestamp with time zone"
+	case "timetz":
+		t.Base = "time with time zone"
+	case "timestamp without time zone":
+		t.Base = "timestamp"
+	case "time without time zone":
+		t.Base = "time"
+	}
+
+	switch t.Base {
+	case "character", "bit":
+		if t.Length == -1 {
+			t.Length = 1
+		}
+	case "numeric":
+		if t.Precision != -1 && t.Scale == -1 {
+			t.Scale = 0
+		}
+	case "timestamp", "timestamp with time zone", "time", "time with time zone":
+		// Default precision of the time types
+		if t.Length == 6 {
+			t.Length = -1
+		}
+	}
+	return t
+}
+
+func (PostgresDialect) CreateVersionTableQuery() string {
+	return `CREATE TABLE IF NOT EXISTS "schema_migrations" (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL);` + "\n"
+}
+
+func (PostgresDialect) InsertVersionQuery() string {
+	return `INSERT INTO "schema_migrations" (version, dirty) VALUES ($1, $2)`
+}
+
+func (PostgresDialect) SplitStatements(script string) ([]string, error) {
+	return ddl.SplitPostgresStatements(script)
+}
+
+func (PostgresDialect) TableNames(ctx context.Context, db *sql.DB, schemaName string) ([]string, error) {
+	query := `SELECT table_name FROM information_schema.tables
+        WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+        ORDER BY table_name`
+	rows, err := db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, &IntrospectionError{Query: query, Err: err}
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+
+	return names, nil
+}
+
+func (PostgresDialect) TableComments(ctx context.Context, db *sql.DB, schemaName string) (map[string]string, error) {
+	query := `SELECT c.relname, obj_description(c.oid, 'pg_class') FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND obj_description(c.oid, 'pg_class') IS NOT NULL`
+	return queryTableComments(ctx, db, query, schemaName)
+}
+
+func (PostgresDialect) DescribeTable(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Column, error) {
+	query := `SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+        pg_get_expr(d.adbin, d.adrelid), a.attidentity::text,
+        COALESCE((SELECT true FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)), false),
+        COALESCE(col_description(c.oid, a.attnum), '')
+        FROM pg_attribute a
+        JOIN pg_class c ON c.oid = a.attrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+        WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+        ORDER BY a.attnum`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var cols []*schema.Column
+	for rows.Next() {
+		var col schema.Column
+		var notNull bool
+		var identity string
+		col.TableName = tableName
+
+		if err := rows.Scan(&col.Name, &col.ColumnType, &notNull, &col.DefaultValue, &identity, &col.PrimaryKey, &col.Comment); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		col.Null = "YES"
+		if notNull {
+			col.Null = "NO"
+		}
+
+		// Both identity and serial (sequence backed) columns are auto increment columns
+		if identity != "" {
+			col.Extra = "auto_increment"
+		} else if col.DefaultValue.Valid && strings.HasPrefix(col.DefaultValue.String, "nextval(") {
+			col.Extra = "auto_increment"
+			col.DefaultValue.Valid = false
+			col.DefaultValue.String = ""
+		}
+		col.DefaultValue = introspectedDefault(col.ColumnType, col.DefaultValue, false)
+
+		cols = append(cols, &col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return cols, nil
+}
+
+func (PostgresDialect) GetPrimaryKey(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]string, error) {
+	query := `SELECT kcu.column_name
+        FROM information_schema.table_constraints tc
+        JOIN information_schema.key_column_usage kcu
+        ON kcu.constraint_name = tc.constraint_name AND kcu.constraint_schema = tc.constraint_schema
+        WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = $1 AND tc.table_name = $2
+        ORDER BY kcu.ordinal_position`
+	return queryPrimaryKey(ctx, db, tableName, query, schemaName, tableName)
+}
+
+// The primary key keeps its name when the table is renamed, so it is not always "<table>_pkey"
+func (PostgresDialect) GetPrimaryKeyName(ctx context.Context, db *sql.DB, schemaName, tableName string) (string, error) {
+	query := `SELECT con.conname
+        FROM pg_constraint con
+        JOIN pg_class c ON c.oid = con.conrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE n.nspname = $1 AND c.relname = $2 AND con.contype = 'p'`
+	var name string
+	err := db.QueryRowContext(ctx, query, schemaName, tableName).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	return name, nil
+}
+
+func (PostgresDialect) GetReferences(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Reference, error) {
+	query := `SELECT rc.constraint_name, rc.update_rule, rc.delete_rule, kcu.table_name, kcu.column_name, ccu.table_name, ccu.column_name
+        FROM information_schema.referential_constraints rc
+        JOIN information_schema.key_column_usage kcu
+        ON kcu.constraint_name = rc.constraint_name AND kcu.constraint_schema = rc.constraint_schema
+        JOIN information_schema.constraint_column_usage ccu
+        ON ccu.constraint_name = rc.constraint_name AND ccu.constraint_schema = rc.constraint_schema
+        WHERE rc.constraint_schema = $1 AND kcu.table_name = $2`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var references []schema.Reference
+	for rows.Next() {
+		var reference schema.Reference
+		err := rows.Scan(&reference.Name, &reference.UpdateOption, &reference.DeleteOption, &reference.TableName, &reference.ColumnName, &reference.ReferencedTableName, &reference.ReferencedColumnName)
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		references = append(references, reference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return references, nil
+}
+
+func (d PostgresDialect) GetUniqueIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) (map[string][]string, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.IndexToUniqueCols, nil
+}
+
+func (d PostgresDialect) GetIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.Indexes, nil
+}
+
+// Expressions are reported the way postgres rewrites them. e.g. ((age > 13))
+func (PostgresDialect) GetChecks(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Check, error) {
+	query := `SELECT con.conname, pg_get_constraintdef(con.oid)
+        FROM pg_constraint con
+        JOIN pg_class c ON c.oid = con.conrelid
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE n.nspname = $1 AND c.relname = $2 AND con.contype = 'c'
+        ORDER BY con.conname`
+	checks, err := queryChecks(ctx, db, tableName, query, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range checks {
+		checks[i].Expression = strings.TrimPrefix(checks[i].Expression, "CHECK ")
+	}
+	return checks, nil
+}
+
+// Returns every index of the table except the primary key, including the indexes of unique constraints
+func (PostgresDialect) indexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	query := `SELECT ic.relname, i.indisunique, a.attname, (i.indoption[k.ord - 1] & 1) = 1, am.amname
+        FROM pg_index i
+        JOIN pg_class tc ON tc.oid = i.indrelid
+        JOIN pg_namespace n ON n.oid = tc.relnamespace
+        JOIN pg_class ic ON ic.oid = i.indexrelid
+        JOIN pg_am am ON am.oid = ic.relam
+        JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+        JOIN pg_attribute a ON a.attrelid = tc.oid AND a.attnum = k.attnum
+        WHERE n.nspname = $1 AND tc.relname = $2 AND NOT i.indisprimary
+        ORDER BY ic.relname, k.ord`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var indexes []*schema.Index
+	for rows.Next() {
+		var name, columnName, method string
+		var unique, desc bool
+		if err := rows.Scan(&name, &unique, &columnName, &desc, &method); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, &schema.Index{Name: name, Unique: unique, Type: schema.IndexType(strings.ToUpper(method))})
+		}
+		index := indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, schema.IndexColumn{Name: columnName, Priority: len(index.Columns), Desc: desc})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return indexes, nil
+}
+
+// Returns the column definition used by CREATE TABLE and ADD COLUMN
+func (PostgresDialect) columnDefinition(c schema.Column) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", c.Name, c.ColumnType))
+
+	if c.Null == "NO" {
+		sb.WriteString(" NOT NULL")
+	}
+	if strings.Contains(c.Extra, "auto_increment") {
+		sb.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	} else if c.DefaultValue.Valid {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(c.DefaultValue.String)
+	}
+	return sb.String()
+}
+
+// Returns the query that sets the comment of the column, an empty comment removes it
+func (PostgresDialect) commentQuery(t schema.Table, c schema.Column) string {
+	comment := "NULL"
+	if c.Comment != "" {
+		comment = quoteString(c.Comment)
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n", t.Name, c.Name, comment)
+}
+
+func (PostgresDialect) DropTableQuery(t *schema.Table) (string, error) {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", t.Name), nil
+}
+
+func (d PostgresDialect) CreateTableQuery(t *schema.Table) (string, error) {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, d.columnDefinition(*c))
+	}
+
+	if len(t.PrimaryCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(t.PrimaryCols, ", ")))
+	}
+
+	for _, iName := range t.UniqueIndexNames() {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT \"%s\" UNIQUE (%s)", iName, strings.Join(t.IndexToUniqueCols[iName], ", ")))
+	}
+
+	for _, reference := range t.References {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT \"%s\" FOREIGN KEY (%s) REFERENCES %s(%s) ON DELETE %s ON UPDATE %s",
+			reference.Name, reference.ColumnName,
+			reference.ReferencedTableName, reference.ReferencedColumnName, reference.DeleteOption, reference.UpdateOption))
+	}
+
+	for _, check := range t.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT \"%s\" CHECK (%s)", check.Name, check.Expression))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);\n", t.Name, strings.Join(defs, ",\n\t")))
+	for _, c := range t.Columns {
+		if c.Comment != "" {
+			sb.WriteString(d.commentQuery(*t, *c))
+		}
+	}
+	for _, index := range t.Indexes {
+		query, err := d.AddIndexQuery(*t, *index)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(query)
+	}
+	return sb.String(), nil
+}
+
+func (d PostgresDialect) AddColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s\n\t", t.Name))
+	sb.WriteString(fmt.Sprintf("ADD COLUMN %s", d.columnDefinition(c)))
+	sb.WriteString(";\n")
+	if c.Comment != "" {
+		sb.WriteString(d.commentQuery(t, c))
+	}
+	return sb.String(), nil
+}
+
+func (PostgresDialect) DropColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP COLUMN %s;\n", t.Name, c.Name), nil
+}
+
+// Postgres alters each property of a column separately, so only the changed properties are altered
+func (d PostgresDialect) ModifyColumnQuery(t schema.Table, c schema.Column, old schema.Column) (string, error) {
+	var actions []string
+
+	if !schema.SameColumnType(c.ColumnType, old.ColumnType, d) {
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::%s", c.Name, c.ColumnType, c.Name, c.ColumnType))
+	}
+
+	if c.Null != old.Null && !c.PrimaryKey {
+		if c.Null == "NO" {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", c.Name))
+		} else {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", c.Name))
+		}
+	}
+
+	isIdentity := strings.Contains(c.Extra, "auto_increment")
+	wasIdentity := strings.Contains(old.Extra, "auto_increment")
+	if wasIdentity && !isIdentity {
+		// Serial columns keep their sequence as the default value, drop it as well
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP IDENTITY IF EXISTS", c.Name))
+		if !c.DefaultValue.Valid {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", c.Name))
+		}
+	}
+
+	if !schema.DefaultEquals(c.DefaultValue, old.DefaultValue) && !isIdentity {
+		if c.DefaultValue.Valid {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", c.Name, c.DefaultValue.String))
+		} else if !wasIdentity {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", c.Name))
+		}
+	}
+
+	if isIdentity && !wasIdentity {
+		if old.DefaultValue.Valid {
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", c.Name))
+		}
+		actions = append(actions, fmt.Sprintf("ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY", c.Name))
+	}
+
+	var query string
+	if len(actions) > 0 {
+		query = fmt.Sprintf("ALTER TABLE %s\n\t%s;\n", t.Name, strings.Join(actions, ",\n\t"))
+	}
+	if c.Comment != old.Comment {
+		query += d.commentQuery(t, c)
+	}
+	return query, nil
+}
+
+func (PostgresDialect) RenameColumnQuery(t schema.Table, newCol schema.Column, oldColumn schema.Column) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tRENAME COLUMN %s TO %s;\n", t.Name, oldColumn.Name, newCol.Name), nil
+}
+
+func (PostgresDialect) RenameTableQuery(oldName, newName string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tRENAME TO %s;\n", oldName, newName), nil
+}
+
+func (PostgresDialect) AddReferenceQuery(reference schema.Reference) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD CONSTRAINT \"%s\" FOREIGN KEY (%s) REFERENCES %s(%s) ON DELETE %s ON UPDATE %s;\n",
+		reference.TableName, reference.Name,
+		reference.ColumnName, reference.ReferencedTableName,
+		reference.ReferencedColumnName, reference.DeleteOption, reference.UpdateOption), nil
+}
+
+// Unlike MySQL, postgres does not create an index for the foreign key, so only the constraint is dropped
+func (PostgresDialect) DropReferenceQuery(reference schema.Reference) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP CONSTRAINT \"%s\";\n", reference.TableName, reference.Name), nil
+}
+
+func (PostgresDialect) AddUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD CONSTRAINT \"%s\" UNIQUE (%s);\n",
+		table.Name, indexName, strings.Join(colNames, ", ")), nil
+}
+
+// Foreign keys don't depend on the unique constraint in postgres, it can be dropped directly.
+// Unique indexes that are not constraints, such as the ones gorm creates, are dropped by DROP INDEX
+func (PostgresDialect) DropUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP CONSTRAINT IF EXISTS \"%s\";\nDROP INDEX IF EXISTS \"%s\";\n", table.Name, indexName, indexName), nil
+}
+
+// Postgres has no FULLTEXT or SPATIAL indexes and no prefix indexes, other index methods are used as given
+func (PostgresDialect) AddIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	if index.Type == schema.FULLTEXT_INDEX || index.Type == schema.SPATIAL_INDEX {
+		return "", &UnsupportedOperationError{Operation: "CREATE INDEX", Table: table.Name, Reason: fmt.Sprintf("postgres has no %s index, use a gin or gist index type instead", index.Type)}
+	}
+
+	var cols []string
+	for _, col := range index.Columns {
+		if col.Length > 0 {
+			return "", &UnsupportedOperationError{Operation: "CREATE INDEX", Table: table.Name, Column: col.Name, Reason: "postgres does not support index prefix length"}
+		}
+		if col.Desc {
+			cols = append(cols, col.Name+" DESC")
+		} else {
+			cols = append(cols, col.Name)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if index.Unique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString(fmt.Sprintf("INDEX \"%s\" ON %s", index.Name, table.Name))
+	if index.Type != "" && index.Type != schema.BTREE_INDEX {
+		sb.WriteString(fmt.Sprintf(" USING %s", strings.ToLower(string(index.Type))))
+	}
+	sb.WriteString(fmt.Sprintf(" (%s);\n", strings.Join(cols, ", ")))
+
+	return sb.String(), nil
+}
+
+func (PostgresDialect) DropIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	return fmt.Sprintf("DROP INDEX IF EXISTS \"%s\";\n", index.Name), nil
+}
+
+func (PostgresDialect) AddCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD CONSTRAINT \"%s\" CHECK (%s);\n", table.Name, check.Name, check.Expression), nil
+}
+
+func (PostgresDialect) DropCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP CONSTRAINT \"%s\";\n", table.Name, check.Name), nil
+}
+
+func (PostgresDialect) AddPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	var names []string
+	for _, c := range cols {
+		names = append(names, c.Name)
+	}
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD PRIMARY KEY (%s);\n", table.Name, strings.Join(names, ", ")), nil
+}
+
+// Primary key constraint is dropped by its introspected name. Primary keys of the models have the default name
+// postgres gives them, "<table>_pkey"
+func (PostgresDialect) DropPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	name := cmp.Or(table.PrimaryKeyName, table.Name+"_pkey")
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP CONSTRAINT \"%s\";\n", table.Name, name), nil
+}
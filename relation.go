@@ -0,0 +1,121 @@
+package migrator
+
+import (
+	"github.com/AkifSahn/migrator/schema"
+	"reflect"
+	"slices"
+)
+
+// Relation field of a model. e.g. 'Company Company', 'Orders []Order'.
+// Relations are resolved into foreign keys after every model is parsed, since the foreign key may be a field of either model
+type relationField struct {
+	Table        *schema.Table // Table of the model that declares the field
+	ModelName    string        // e.g. "User"
+	FieldName    string        // e.g. "Company"
+	Related      reflect.Type  // Model type of the field. e.g. 'Company'
+	Many         bool          // Slice fields are has many relations, struct fields are has one or belongs to relations
+	ForeignKey   string        // Foreign key field set by the 'foreignKey' tag
+	References   string        // Referenced field set by the 'references' tag
+	DeleteOption schema.ReferenceOption
+	UpdateOption schema.ReferenceOption
+}
+
+// Resolves the relation fields of the parsed models into foreign keys, the same way gorm guesses them:
+//   - has one and has many: the related model has a '<Model><PrimaryKey>' field. e.g. 'UserID' of 'Company' for 'User.Company'
+//   - belongs to: the model has a '<Field><RelatedPrimaryKey>' field. e.g. 'CompanyID' of 'User' for 'User.Company'
+//
+// Struct fields are tried as has one first and as belongs to if the related model has no foreign key field,
+// self referencing fields are tried the other way around. 'foreignKey' and 'references' tags name the fields of the relation.
+// A column that is the foreign key of more than one relation gets the foreign key of the first guess that finds it.
+// Relations to models that are not given, or without a foreign key field, are skipped
+func (m *Migrator) resolveRelations(tables []*schema.Table) error {
+	var fallbacks []func() bool
+	for _, rel := range m.relationFields {
+		relatedName := m.tableName(rel.Related)
+		i := slices.IndexFunc(tables, func(t *schema.Table) bool { return t.Name == relatedName })
+		if i == -1 {
+			// Related model is not one of the given structs
+			continue
+		}
+		related := tables[i]
+
+		has := func() bool { return m.addRelation(rel, related, rel.Table, rel.ModelName, !rel.Many) }
+		belongsTo := func() bool { return !rel.Many && m.addRelation(rel, rel.Table, related, rel.FieldName, false) }
+		if related == rel.Table {
+			has, belongsTo = belongsTo, has
+		}
+		if !has() {
+			fallbacks = append(fallbacks, belongsTo)
+		}
+	}
+
+	// Fallback guesses come after every first guess, so the chosen foreign keys don't depend on the order of the models
+	for _, fallback := range fallbacks {
+		fallback()
+	}
+	return nil
+}
+
+// Adds the foreign key of the relation field to 'fkTable', referencing the 'referenced' table.
+// Foreign key field is the 'foreignKey' tag or 'prefix' followed by the referenced field, the primary key unless 'references' is set.
+// Returns false if 'fkTable' doesn't have the foreign key field
+func (m *Migrator) addRelation(rel relationField, fkTable, referenced *schema.Table, prefix string, unique bool) bool {
+	refCol := referenced.GetPrimaryKeyColumn()
+	if rel.References != "" {
+		refCol = m.relationColumn(referenced, rel.References)
+	}
+	if refCol == nil {
+		return false
+	}
+
+	fkField := rel.ForeignKey
+	if fkField == "" {
+		fkField = prefix + refCol.FieldName
+	}
+	col := m.relationColumn(fkTable, fkField)
+	if col == nil || col == refCol {
+		return false
+	}
+
+	// Column is already used by another relation field
+	if col.ForeignKey {
+		return true
+	}
+
+	reference := schema.Reference{
+		Name:                 m.foreignKeyName(rel.Table.Name, rel.FieldName, fkTable.Name, col.Name),
+		TableName:            fkTable.Name,
+		ColumnName:           col.Name,
+		ReferencedTableName:  referenced.Name,
+		ReferencedColumnName: refCol.Name,
+		DeleteOption:         rel.DeleteOption,
+		UpdateOption:         rel.UpdateOption,
+		IsUnique:             unique,
+	}
+	fkTable.References = append(fkTable.References, reference)
+	m.Relations = append(m.Relations, reference)
+	col.ForeignKey = true
+
+	// Foreign key has the type of the referenced column, the same way gorm does.
+	// e.g. bigint for an unsigned field that references a postgres identity column, instead of numeric(20)
+	col.ColumnType = refCol.ColumnType
+
+	if unique && !col.PrimaryKey {
+		if !col.UniqueIndex {
+			fkTable.IndexToUniqueCols[m.namer().UniqueName(fkTable.Name, col.FieldName)] = []string{col.Name}
+		}
+		col.UniqueIndex = true
+	}
+	return true
+}
+
+// Returns the column of the given field or column name. Nil if the table doesn't have it
+func (m *Migrator) relationColumn(t *schema.Table, name string) *schema.Column {
+	if i := slices.IndexFunc(t.Columns, func(c *schema.Column) bool { return c.FieldName == name }); i != -1 {
+		return t.Columns[i]
+	}
+	if c := t.GetColumn(name); c != nil {
+		return c
+	}
+	return t.GetColumn(m.namer().ColumnName(t.Name, name))
+}
@@ -0,0 +1,228 @@
+package migrator
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// RenamedFrom is the previous table name of a renamed model. It is declared by a field of the model,
+// e.g. Account{RenamedFrom: migrator.RenamedFrom("users")} or a "_ migrator.RenamedFrom" field with the `migrator:"renamedFrom:users"` tag,
+// or by a 'RenamedFrom' method, see 'TableRenamer'. Marker fields are not columns
+type RenamedFrom string
+
+// TableRenamer is implemented by the models whose table is renamed
+type TableRenamer interface {
+	RenamedFrom() RenamedFrom
+}
+
+var renamedFromType = reflect.TypeOf(RenamedFrom(""))
+
+// Returns the previous table name the given model declares by a 'RenamedFrom' field or method, empty if there is none
+func tableRenamedFrom(dst interface{}) (string, error) {
+	if renamer, ok := dst.(TableRenamer); ok {
+		return string(renamer.RenamedFrom()), nil
+	}
+	value := reflect.New(reflect.TypeOf(dst))
+	value.Elem().Set(reflect.ValueOf(dst))
+	if renamer, ok := value.Interface().(TableRenamer); ok {
+		return string(renamer.RenamedFrom()), nil
+	}
+
+	for i := 0; i < value.Elem().NumField(); i++ {
+		field := value.Type().Elem().Field(i)
+		if field.Type != renamedFromType {
+			continue
+		}
+		if name := value.Elem().Field(i).String(); name != "" {
+			return name, nil
+		}
+		name, err := parseMigratorTag(field.Tag.Get("migrator"))
+		if err != nil {
+			return "", &TagParseError{Model: value.Type().Elem().Name(), Field: field.Name, Tag: field.Tag.Get("migrator"), Err: err}
+		}
+		return name, nil
+	}
+	return "", nil
+}
+
+// Parses the value of the 'migrator' tag and returns the previous name it declares. e.g. `migrator:"renamedFrom:full_name"`.
+// The tag is split by 'parseTagSettings', so it is escaped the same way as gorm tags
+func parseMigratorTag(tag string) (string, error) {
+	var renamedFrom string
+	for _, setting := range parseTagSettings(tag) {
+		switch setting.Key {
+		case "RENAMEDFROM":
+			renamedFrom = strings.TrimSpace(setting.Value)
+			if renamedFrom == "" {
+				return "", fmt.Errorf("renamedFrom requires the previous name")
+			}
+		default:
+			return "", fmt.Errorf("unknown migrator tag option %q", strings.ToLower(setting.Key))
+		}
+	}
+	return renamedFrom, nil
+}
+
+// Pairs the dropped columns with the added columns of the same definition in the same table.
+// Confirmed pairs are marked as renamed by setting 'Column.RenamedFrom' of the model column.
+// Pairs that are not unique are left as they are
+func (m *Migrator) detectRenames(pairs []*schema.TablePair) error {
+	for _, pair := range pairs {
+		current, model := pair.First, pair.Second
+
+		var dropped, added []*schema.Column
+		for _, c := range current.Columns {
+			if model.GetColumn(c.Name) == nil && !slices.ContainsFunc(model.Columns, func(mc *schema.Column) bool { return mc.RenamedFrom == c.Name }) {
+				dropped = append(dropped, c)
+			}
+		}
+		for _, c := range model.Columns {
+			if current.GetColumn(c.Name) == nil && c.RenamedFrom == "" {
+				added = append(added, c)
+			}
+		}
+
+		for _, old := range dropped {
+			matches := slices.DeleteFunc(slices.Clone(added), func(c *schema.Column) bool { return !sameDefinition(*old, *c, m.Dialect) })
+			if len(matches) != 1 {
+				continue
+			}
+			newCol := matches[0]
+			if slices.ContainsFunc(dropped, func(c *schema.Column) bool { return c != old && sameDefinition(*c, *newCol, m.Dialect) }) {
+				continue
+			}
+
+			if m.Confirm == nil {
+				return &RenameError{Table: current.Name, From: old.Name, To: newCol.Name}
+			}
+			confirmed, err := m.Confirm(fmt.Sprintf("Is column %s.%s renamed to %s?", current.Name, old.Name, newCol.Name))
+			if err != nil {
+				return err
+			}
+			if confirmed {
+				newCol.RenamedFrom = old.Name
+			}
+		}
+	}
+	return nil
+}
+
+// Returns true if the columns only differ by name. Column types are compared by the given rules
+func sameDefinition(a, b schema.Column, rules schema.TypeRules) bool {
+	return !a.PrimaryKey && !b.PrimaryKey &&
+		schema.SameColumnType(a.ColumnType, b.ColumnType, rules) &&
+		strings.EqualFold(a.Null, b.Null) &&
+		strings.EqualFold(a.Extra, b.Extra) &&
+		schema.DefaultEquals(a.DefaultValue, b.DefaultValue)
+}
+
+// Returns a confirm function that asks the question on out and reads the answer from in. Only "y" and "yes" confirm
+func promptConfirm(in *bufio.Reader, out io.Writer) func(question string) (bool, error) {
+	return func(question string) (bool, error) {
+		fmt.Fprintf(out, "%s [y/N]: ", question)
+		answer, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, fmt.Errorf("reading answer: %w", err)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes", nil
+	}
+}
+
+// Returns the 'map[oldName] -> newName' of the database tables renamed by the 'RenamedFrom' of the given models.
+// A table is renamed only if its old name exists and its new name doesn't exist in the database
+func renamedTables(dbTables, dst []*schema.Table) (map[string]string, error) {
+	exists := func(tables []*schema.Table, name string) bool {
+		return slices.ContainsFunc(tables, func(t *schema.Table) bool { return t.Name == name })
+	}
+
+	renamed := make(map[string]string)
+	for _, t := range dst {
+		if t.RenamedFrom == "" || t.RenamedFrom == t.Name || exists(dbTables, t.Name) || !exists(dbTables, t.RenamedFrom) {
+			continue
+		}
+		if exists(dst, t.RenamedFrom) {
+			return nil, &ModelError{Model: t.Name, Reason: fmt.Sprintf("table %s is renamed, but another model still uses the name", t.RenamedFrom)}
+		}
+		if other, ok := renamed[t.RenamedFrom]; ok {
+			return nil, &ModelError{Model: t.Name, Reason: fmt.Sprintf("table %s is already renamed to %s", t.RenamedFrom, other)}
+		}
+		renamed[t.RenamedFrom] = t.Name
+	}
+	return renamed, nil
+}
+
+// Returns copies of the given database tables as if the renamed tables already had their new names.
+// Foreign keys of every table that reference a renamed table are updated the same way the database does
+func renameTables(tables []*schema.Table, renamed map[string]string) []*schema.Table {
+	if len(renamed) == 0 {
+		return tables
+	}
+
+	result := make([]*schema.Table, 0, len(tables))
+	for _, t := range tables {
+		table := *t
+		if newName, ok := renamed[t.Name]; ok {
+			table.Name = newName
+			table.Columns = make([]*schema.Column, 0, len(t.Columns))
+			for _, c := range t.Columns {
+				col := *c
+				col.TableName = newName
+				table.Columns = append(table.Columns, &col)
+			}
+		}
+
+		table.References = slices.Clone(t.References)
+		for i, r := range table.References {
+			if newName, ok := renamed[r.TableName]; ok {
+				table.References[i].TableName = newName
+			}
+			if newName, ok := renamed[r.ReferencedTableName]; ok {
+				table.References[i].ReferencedTableName = newName
+			}
+		}
+		result = append(result, &table)
+	}
+	return result
+}
+
+// Returns the migrations that rename the foreign keys of or to the renamed tables,
+// whose constraint names are different in the dst table. e.g. "fk_users_orders" -> "fk_accounts_orders".
+// Constraints are renamed by dropping and adding them, foreign keys already changed by the migrations are skipped
+func renamedReferenceMigrations(migrations []*schema.ColumnMigration, t, dst *schema.Table, renamed map[string]string) []*schema.ColumnMigration {
+	isRenamed := func(name string) bool {
+		for _, newName := range renamed {
+			if newName == name {
+				return true
+			}
+		}
+		return false
+	}
+	sameKey := func(a, b schema.Reference) bool {
+		return a.TableName == b.TableName && a.ColumnName == b.ColumnName &&
+			a.ReferencedTableName == b.ReferencedTableName && a.ReferencedColumnName == b.ReferencedColumnName
+	}
+
+	for _, r := range dst.References {
+		if !isRenamed(r.TableName) && !isRenamed(r.ReferencedTableName) {
+			continue
+		}
+		i := slices.IndexFunc(t.References, func(old schema.Reference) bool { return sameKey(old, r) })
+		if i == -1 || t.References[i].Name == r.Name || t.References[i].Name == "" || r.Name == "" {
+			continue
+		}
+		if slices.ContainsFunc(migrations, func(migration *schema.ColumnMigration) bool {
+			applied, ok := migration.ApplyOn.(schema.Reference)
+			return ok && sameKey(applied, r)
+		}) {
+			continue
+		}
+		migrations = append(migrations, schema.NewColumnMigration(r, t.References[i], schema.UPDATE_FOREIGN_KEY))
+	}
+	return migrations
+}
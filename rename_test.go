@@ -0,0 +1,125 @@
+package migrator
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/AkifSahn/migrator/schema"
+	"slices"
+	"testing"
+)
+
+// Returns a users table with the given "name type" columns, after an "id" primary key
+func renameTable(columns ...[2]string) *schema.Table {
+	t := &schema.Table{Name: "users", PrimaryCols: []string{"id"}}
+	t.Columns = append(t.Columns, &schema.Column{TableName: "users", Name: "id", ColumnType: "bigint", Null: "NO", PrimaryKey: true})
+	for _, c := range columns {
+		t.Columns = append(t.Columns, &schema.Column{TableName: "users", Name: c[0], ColumnType: c[1], Null: "YES"})
+	}
+	return t
+}
+
+func TestDetectRenames(t *testing.T) {
+	tests := []struct {
+		name    string
+		current *schema.Table
+		model   *schema.Table
+		answer  bool
+		asked   []string
+		renamed map[string]string // New column -> old column
+	}{
+		{"confirmed", renameTable([2]string{"full_name", "varchar(255)"}), renameTable([2]string{"name", "varchar(255)"}),
+			true, []string{"Is column users.full_name renamed to name?"}, map[string]string{"name": "full_name"}},
+		{"declined", renameTable([2]string{"full_name", "varchar(255)"}), renameTable([2]string{"name", "varchar(255)"}),
+			false, []string{"Is column users.full_name renamed to name?"}, nil},
+		{"same type by spelling", renameTable([2]string{"full_name", "VARCHAR (255)"}), renameTable([2]string{"name", "varchar(255)"}),
+			true, []string{"Is column users.full_name renamed to name?"}, map[string]string{"name": "full_name"}},
+		{"different type", renameTable([2]string{"full_name", "varchar(100)"}), renameTable([2]string{"name", "varchar(255)"}),
+			true, nil, nil},
+		{"two added columns match", renameTable([2]string{"full_name", "text"}), renameTable([2]string{"name", "text"}, [2]string{"bio", "text"}),
+			true, nil, nil},
+		{"two dropped columns match", renameTable([2]string{"first", "text"}, [2]string{"last", "text"}), renameTable([2]string{"name", "text"}),
+			true, nil, nil},
+		{"kept column is not renamed", renameTable([2]string{"name", "text"}), renameTable([2]string{"name", "text"}, [2]string{"bio", "text"}),
+			true, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var asked []string
+			m := &Migrator{Dialect: MySQLDialect{}, Confirm: func(question string) (bool, error) {
+				asked = append(asked, question)
+				return test.answer, nil
+			}}
+			if err := m.detectRenames([]*schema.TablePair{{First: test.current, Second: test.model}}); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(asked, test.asked) {
+				t.Errorf("asked %q, want %q", asked, test.asked)
+			}
+			for _, c := range test.model.Columns {
+				if c.RenamedFrom != test.renamed[c.Name] {
+					t.Errorf("column %s is renamed from %q, want %q", c.Name, c.RenamedFrom, test.renamed[c.Name])
+				}
+			}
+		})
+	}
+
+	// Different defaults are different definitions
+	current := renameTable([2]string{"status", "varchar(20)"})
+	current.Columns[1].DefaultValue = sql.NullString{String: "'new'", Valid: true}
+	m := &Migrator{Dialect: MySQLDialect{}}
+	if err := m.detectRenames([]*schema.TablePair{{First: current, Second: renameTable([2]string{"state", "varchar(20)"})}}); err != nil {
+		t.Errorf("columns with different defaults are detected as renamed: %v", err)
+	}
+
+	// Renames are not guessed without a confirm function
+	err := m.detectRenames([]*schema.TablePair{{First: renameTable([2]string{"full_name", "text"}), Second: renameTable([2]string{"name", "text"})}})
+	var renameErr *RenameError
+	if !errors.As(err, &renameErr) || renameErr.From != "full_name" || renameErr.To != "name" {
+		t.Errorf("got error %v, want *RenameError from full_name to name", err)
+	}
+}
+
+func TestRenamedTables(t *testing.T) {
+	table := func(name, renamedFrom string) *schema.Table {
+		return &schema.Table{Name: name, RenamedFrom: renamedFrom}
+	}
+
+	tests := []struct {
+		name     string
+		dbTables []*schema.Table
+		dst      []*schema.Table
+		want     map[string]string
+		fails    bool
+	}{
+		{"renamed", []*schema.Table{table("users", "")}, []*schema.Table{table("accounts", "users")}, map[string]string{"users": "accounts"}, false},
+		{"already renamed", []*schema.Table{table("accounts", "")}, []*schema.Table{table("accounts", "users")}, map[string]string{}, false},
+		{"both names exist", []*schema.Table{table("users", ""), table("accounts", "")}, []*schema.Table{table("accounts", "users")}, map[string]string{}, false},
+		{"old name is used by another model", []*schema.Table{table("users", "")}, []*schema.Table{table("accounts", "users"), table("users", "")}, nil, true},
+		{"renamed twice", []*schema.Table{table("users", "")}, []*schema.Table{table("accounts", "users"), table("members", "users")}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := renamedTables(test.dbTables, test.dst)
+			if test.fails {
+				var modelErr *ModelError
+				if !errors.As(err, &modelErr) {
+					t.Errorf("got error %v, want *ModelError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("renamed tables %v, want %v", got, test.want)
+			}
+			for old, newName := range test.want {
+				if got[old] != newName {
+					t.Errorf("renamed tables %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
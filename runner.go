@@ -0,0 +1,301 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Name of the table that stores the applied migration version, same as golang-migrate
+const VERSION_TABLE = "schema_migrations"
+
+// Version golang-migrate stores while the first migration is reverted. Migrator reports it as version 0
+const NIL_VERSION = -1
+
+// SQLite reports foreign key violations as rows instead of failing. e.g. PRAGMA foreign_key_check
+var foreignKeyCheckRegexp = regexp.MustCompile(`(?i)^PRAGMA\s+foreign_key_check\b`)
+
+// Runs the statements of a script, '*sql.Conn' or '*sql.DB'
+type statementRunner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Applies the next n up migrations of the migrations directory. If n <= 0 every pending migration is applied
+func (m *Migrator) Up(n int) error {
+	ctx := context.Background()
+	migrations, current, err := m.loadMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := len(migrations) - 1
+	if n > 0 {
+		target = min(current+n, target)
+	}
+	return m.migrate(ctx, migrations, current, target)
+}
+
+// Reverts the last n applied migrations by running their down scripts. If n <= 0 every migration is reverted
+func (m *Migrator) Down(n int) error {
+	ctx := context.Background()
+	migrations, current, err := m.loadMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := -1
+	if n > 0 {
+		target = max(current-n, -1)
+	}
+	return m.migrate(ctx, migrations, current, target)
+}
+
+// Migrates the database up or down to the given version. Version 0 reverts every migration
+func (m *Migrator) Goto(version int64) error {
+	ctx := context.Background()
+	migrations, current, err := m.loadMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := -1
+	if version != 0 {
+		target = slices.IndexFunc(migrations, func(migration Migration) bool { return migration.Version == version })
+		if target == -1 {
+			return fmt.Errorf("no migration found for version %d", version)
+		}
+	}
+	return m.migrate(ctx, migrations, current, target)
+}
+
+// Sets the version of the database and clears the dirty flag without running any migration.
+// Used to recover after a migration failed half way and the database is fixed by hand
+func (m *Migrator) Force(version int64) error {
+	if m.IsOffline() {
+		return errors.New("forcing a version requires a database connection")
+	}
+	if version < 0 {
+		return fmt.Errorf("invalid version %d", version)
+	}
+
+	ctx := context.Background()
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.createVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	if err := m.setVersion(ctx, conn, version, false); err != nil {
+		return err
+	}
+	m.CurrentVersion = version
+	return nil
+}
+
+// Returns the migrations of the migrations directory, read by 'Migrator.Writer', and the index of the applied migration
+// in them, -1 if no migration is applied. Fails if the database is dirty or the applied migration is missing from the directory
+func (m *Migrator) loadMigrations(ctx context.Context) ([]Migration, int, error) {
+	if m.IsOffline() {
+		return nil, 0, errors.New("running migrations requires a database connection")
+	}
+	if m.MigrationsDir == "" {
+		return nil, 0, errors.New("migrations directory is not set")
+	}
+
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+
+	if err := m.createVersionTable(ctx, conn); err != nil {
+		return nil, 0, err
+	}
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return nil, 0, err
+	}
+	if dirty {
+		return nil, 0, &DirtyError{Version: version}
+	}
+	m.CurrentVersion = version
+
+	migrations, err := m.writer().ReadMigrations(m.MigrationsDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == 0 {
+		return migrations, -1, nil
+	}
+
+	current := slices.IndexFunc(migrations, func(migration Migration) bool { return migration.Version == version })
+	if current == -1 {
+		return nil, 0, fmt.Errorf("no migration found for the current version %d in %s", version, m.MigrationsDir)
+	}
+	return migrations, current, nil
+}
+
+// Runs the migrations between the current and the target index of the migrations in order.
+// Up scripts are run if the target is after the current index, down scripts if it is before
+func (m *Migrator) migrate(ctx context.Context, migrations []Migration, current, target int) error {
+	for current < target {
+		current++
+		migration := migrations[current]
+		if strings.TrimSpace(migration.Up) == "" {
+			return fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		name := fmt.Sprintf("%d_%s up script", migration.Version, migration.Name)
+		if err := m.runMigration(ctx, name, migration.Up, migration.Version); err != nil {
+			return err
+		}
+	}
+
+	for current > target {
+		migration := migrations[current]
+		// e.g. Atlas plans down migrations itself, its files have no down script
+		if strings.TrimSpace(migration.Down) == "" {
+			return fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		name := fmt.Sprintf("%d_%s down script", migration.Version, migration.Name)
+
+		// Reverting a migration moves the database to the version of the previous migration
+		var previous int64
+		if current > 0 {
+			previous = migrations[current-1].Version
+		}
+		if err := m.runMigration(ctx, name, migration.Down, previous); err != nil {
+			return err
+		}
+		current--
+	}
+
+	return nil
+}
+
+// Runs the statements of the given script one by one and sets the database version.
+// Version is marked dirty until every statement succeeds, the same way golang-migrate does
+func (m *Migrator) runMigration(ctx context.Context, name, script string, version int64) error {
+	statements, err := m.Dialect.SplitStatements(script)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	// Statements run on a single connection, since scripts may change session settings. e.g. "PRAGMA foreign_keys"
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if err := execStatement(ctx, conn, statement); err != nil {
+			return fmt.Errorf("running %s: %w in statement: %s", name, err, statement)
+		}
+	}
+	if err := m.setVersion(ctx, conn, version, false); err != nil {
+		return err
+	}
+
+	m.CurrentVersion = version
+	if m.Log != nil {
+		fmt.Fprintln(m.Log, "Applied migration:", name)
+	}
+	return nil
+}
+
+// Runs a single statement of a script. Foreign key violations reported by 'PRAGMA foreign_key_check' are returned as an error
+func execStatement(ctx context.Context, runner statementRunner, statement string) error {
+	if !foreignKeyCheckRegexp.MatchString(statement) {
+		_, err := runner.ExecContext(ctx, statement)
+		return err
+	}
+
+	rows, err := runner.QueryContext(ctx, statement)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var violations []string
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return err
+		}
+		violations = append(violations, fmt.Sprintf("row %d of %s references a missing row of %s", rowID.Int64, table, parent))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("foreign key check failed: %s", strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+func (m *Migrator) createVersionTable(ctx context.Context, conn *sql.Conn) error {
+	statements, err := m.Dialect.SplitStatements(m.Dialect.CreateVersionTableQuery())
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("creating %s table: %w", VERSION_TABLE, err)
+		}
+	}
+	return nil
+}
+
+// Returns the version stored in the version table. Empty table means no migration is applied, version 0
+func readVersion(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	query := fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", VERSION_TABLE)
+	err := conn.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("reading version: %w", err)
+	}
+
+	if version == NIL_VERSION {
+		version = 0
+	}
+	return version, dirty, nil
+}
+
+// Replaces the stored version. Like golang-migrate, version table is left empty when no migration is applied
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", VERSION_TABLE)); err != nil {
+		return fmt.Errorf("setting version: %w", err)
+	}
+	if version > 0 || dirty {
+		if version == 0 {
+			version = NIL_VERSION
+		}
+		if _, err := tx.ExecContext(ctx, m.Dialect.InsertVersionQuery(), version, dirty); err != nil {
+			return fmt.Errorf("setting version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ColumnType is the parsed form of a column type. e.g. "DECIMAL(10,2) UNSIGNED", "varchar(255) CHARACTER SET utf8mb4"
+type ColumnType struct {
+	Base      string   // Lower cased name of the type without its arguments and attributes. e.g. "decimal", "timestamp with time zone"
+	Length    int      // Length, display width or fractional seconds. e.g. varchar(255), int(11), datetime(3). -1 if not given
+	Precision int      // Precision of the numeric types. e.g. 10 of decimal(10,2), 24 of float(24). -1 if not given
+	Scale     int      // Scale of the numeric types. e.g. 2 of decimal(10,2). -1 if not given
+	Args      []string // Arguments that are not sizes. e.g. the quoted values of enum('a','b')
+	Unsigned  bool
+	Zerofill  bool
+	Charset   string // Lower cased, empty if not given
+	Collation string // Lower cased, empty if not given
+}
+
+// TypeRules tells which spellings of column types are the same type in a database engine
+type TypeRules interface {
+	// Returns the canonical form of the given column type, so different spellings of the same type are equal.
+	// e.g. "integer" and "int(11)" are both "int" for MySQL
+	CanonicalType(t ColumnType) ColumnType
+}
+
+// Types whose arguments are a precision and a scale instead of a length
+var numericTypes = []string{"decimal", "numeric", "dec", "fixed", "float", "double", "double precision", "real"}
+
+var quotedArgRegexp = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// Parses the given column type. Case and spacing of the type are ignored.
+// e.g. "VARCHAR (255)" -> varchar, length 255. "timestamp(3) with time zone" -> timestamp with time zone, length 3
+func ParseColumnType(columnType string) ColumnType {
+	t := ColumnType{Length: -1, Precision: -1, Scale: -1}
+
+	columnType = strings.TrimSpace(columnType)
+	var args string
+	if start := strings.Index(columnType, "("); start != -1 {
+		if end := ClosingParenthesis(columnType, start); end != -1 {
+			args = columnType[start+1 : end]
+			columnType = columnType[:start] + " " + columnType[end+1:]
+		}
+	}
+
+	var words []string
+	fields := strings.Fields(columnType)
+	for i := 0; i < len(fields); i++ {
+		word := strings.ToLower(fields[i])
+		switch {
+		case word == "unsigned":
+			t.Unsigned = true
+		case word == "zerofill":
+			t.Zerofill = true
+		case word == "signed":
+		case word == "character" && i+2 < len(fields) && strings.EqualFold(fields[i+1], "set"):
+			t.Charset = attributeValue(fields[i+2])
+			i += 2
+		case (word == "charset" || word == "collate") && i+1 < len(fields):
+			if word == "charset" {
+				t.Charset = attributeValue(fields[i+1])
+			} else {
+				t.Collation = attributeValue(fields[i+1])
+			}
+			i++
+		default:
+			words = append(words, word)
+		}
+	}
+	t.Base = strings.Join(words, " ")
+
+	if strings.TrimSpace(args) == "" {
+		return t
+	}
+	if strings.Contains(args, "'") {
+		t.Args = quotedArgRegexp.FindAllString(args, -1)
+		return t
+	}
+
+	var sizes []int
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		t.Args = append(t.Args, arg)
+		if size, err := strconv.Atoi(arg); err == nil {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) != len(t.Args) {
+		// Not sizes. e.g. varchar(max)
+		return t
+	}
+	t.Args = nil
+
+	if slices.Contains(numericTypes, t.Base) {
+		t.Precision = sizes[0]
+		if len(sizes) > 1 {
+			t.Scale = sizes[1]
+		}
+	} else {
+		t.Length = sizes[0]
+	}
+	return t
+}
+
+// Returns the column type in SQL. e.g. "decimal(10,2) unsigned", "timestamp(3) with time zone"
+func (t ColumnType) String() string {
+	var args string
+	switch {
+	case len(t.Args) > 0:
+		args = strings.Join(t.Args, ",")
+	case t.Precision != -1 && t.Scale != -1:
+		args = fmt.Sprintf("%d,%d", t.Precision, t.Scale)
+	case t.Precision != -1:
+		args = strconv.Itoa(t.Precision)
+	case t.Length != -1:
+		args = strconv.Itoa(t.Length)
+	}
+
+	// Time zone comes after the arguments. e.g. time(3) with time zone
+	name, zone := t.Base, ""
+	if before, after, found := strings.Cut(t.Base, " with"); found && strings.HasPrefix(before, "time") {
+		name, zone = before, " with"+after
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	if args != "" {
+		sb.WriteString("(" + args + ")")
+	}
+	sb.WriteString(zone)
+	if t.Unsigned {
+		sb.WriteString(" unsigned")
+	}
+	if t.Zerofill {
+		sb.WriteString(" zerofill")
+	}
+	if t.Charset != "" {
+		sb.WriteString(" CHARACTER SET " + t.Charset)
+	}
+	if t.Collation != "" {
+		sb.WriteString(" COLLATE " + t.Collation)
+	}
+	return sb.String()
+}
+
+// Returns true if both types are the same. Charset and collation are compared only if both types have them,
+// since databases don't always report them
+func (t ColumnType) Equals(other ColumnType) bool {
+	return t.Base == other.Base &&
+		t.Length == other.Length &&
+		t.Precision == other.Precision &&
+		t.Scale == other.Scale &&
+		slices.Equal(t.Args, other.Args) &&
+		t.Unsigned == other.Unsigned &&
+		t.Zerofill == other.Zerofill &&
+		(t.Charset == "" || other.Charset == "" || t.Charset == other.Charset) &&
+		(t.Collation == "" || other.Collation == "" || t.Collation == other.Collation)
+}
+
+// Returns the canonical form of the column type by the given rules. Nil rules only parse the type
+func CanonicalColumnType(columnType string, rules TypeRules) ColumnType {
+	t := ParseColumnType(columnType)
+	if rules != nil {
+		t = rules.CanonicalType(t)
+	}
+	return t
+}
+
+// Returns true if both column types are the same type by the given rules.
+// Nil rules only ignore the spelling. e.g. "VARCHAR (255)" and "varchar(255)"
+func SameColumnType(a, b string, rules TypeRules) bool {
+	return CanonicalColumnType(a, rules).Equals(CanonicalColumnType(b, rules))
+}
+
+// Returns the index of the parenthesis closing the one at start, skipping quoted strings and names. -1 if it is not closed
+func ClosingParenthesis(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Returns the lower cased value of a charset or collation without its quotes. e.g. 'utf8mb4' -> utf8mb4
+func attributeValue(value string) string {
+	return strings.ToLower(strings.Trim(value, "'\"`"))
+}
@@ -0,0 +1,293 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type defaultKind int
+
+// Largest exponent of a numeric default that is written out in decimal form. e.g. 1e5 -> 100000
+const MAX_DEFAULT_EXPONENT = 1000
+
+const (
+	OTHER_DEFAULT defaultKind = iota // Types without a known literal format. e.g. json, blob
+	INTEGER_DEFAULT
+	BOOLEAN_DEFAULT
+	NUMERIC_DEFAULT
+	STRING_DEFAULT
+	DATE_DEFAULT
+)
+
+var defaultKinds = map[string]defaultKind{
+	"tinyint": INTEGER_DEFAULT, "smallint": INTEGER_DEFAULT, "mediumint": INTEGER_DEFAULT, "int": INTEGER_DEFAULT,
+	"integer": INTEGER_DEFAULT, "bigint": INTEGER_DEFAULT, "int2": INTEGER_DEFAULT, "int4": INTEGER_DEFAULT,
+	"int8": INTEGER_DEFAULT, "smallserial": INTEGER_DEFAULT, "serial": INTEGER_DEFAULT, "bigserial": INTEGER_DEFAULT,
+	"year": INTEGER_DEFAULT,
+
+	"boolean": BOOLEAN_DEFAULT, "bool": BOOLEAN_DEFAULT,
+
+	"decimal": NUMERIC_DEFAULT, "numeric": NUMERIC_DEFAULT, "dec": NUMERIC_DEFAULT, "float": NUMERIC_DEFAULT,
+	"double": NUMERIC_DEFAULT, "double precision": NUMERIC_DEFAULT, "real": NUMERIC_DEFAULT,
+	"float4": NUMERIC_DEFAULT, "float8": NUMERIC_DEFAULT,
+
+	"char": STRING_DEFAULT, "varchar": STRING_DEFAULT, "character": STRING_DEFAULT, "character varying": STRING_DEFAULT,
+	"nchar": STRING_DEFAULT, "nvarchar": STRING_DEFAULT, "text": STRING_DEFAULT, "tinytext": STRING_DEFAULT,
+	"mediumtext": STRING_DEFAULT, "longtext": STRING_DEFAULT, "enum": STRING_DEFAULT, "set": STRING_DEFAULT,
+	"uuid": STRING_DEFAULT, "citext": STRING_DEFAULT,
+
+	"date": DATE_DEFAULT, "datetime": DATE_DEFAULT, "timestamp": DATE_DEFAULT, "time": DATE_DEFAULT,
+	"timestamptz": DATE_DEFAULT, "timetz": DATE_DEFAULT,
+	"timestamp with time zone": DATE_DEFAULT, "timestamp without time zone": DATE_DEFAULT,
+	"time with time zone": DATE_DEFAULT, "time without time zone": DATE_DEFAULT,
+}
+
+var (
+	numberRegexp = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+	// Postgres reports literals with their type. e.g. 'hello'::character varying
+	castRegexp = regexp.MustCompile(`^(.*)::[a-zA-Z][\w ]*(\(\d+(,\s*\d+)?\))?(\[\])?$`)
+
+	// Date and time keywords that are allowed without parentheses. e.g. CURRENT_TIMESTAMP(3), now()
+	timeKeywordRegexp = regexp.MustCompile(`(?i)^(current_timestamp|current_date|current_time|localtime|localtimestamp|now)\s*(\(\s*(\d*)\s*\))?$`)
+
+	functionCallRegexp = regexp.MustCompile(`^[a-zA-Z_][\w.]*\s*\(.*\)$`)
+	enumValueRegexp    = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+	dateRegexp     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	dateTimeRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}([ T]\d{2}:\d{2}(:\d{2}(\.\d+)?)?)?([+-]\d{2}(:?\d{2})?|Z)?$`)
+	timeRegexp     = regexp.MustCompile(`^-?\d{1,3}:\d{2}(:\d{2}(\.\d+)?)?([+-]\d{2}(:?\d{2})?)?$`)
+)
+
+// Returns the default value in its canonical SQL form for the given column type, so equal defaults have the same text
+// and the value can be written into a DEFAULT clause as it is. Invalid values for the column type are reported as an error.
+//   - String, date and enum literals are single quoted. e.g. hello -> 'hello', "a" -> 'a'
+//   - Numbers and booleans are validated and written without quotes. e.g. '5' -> 5, 0.00 -> 0, true -> 1 for integer types
+//   - Date and time keywords are upper cased. e.g. now() -> CURRENT_TIMESTAMP, current_timestamp(3) -> CURRENT_TIMESTAMP(3)
+//   - Other expressions are wrapped in parentheses. e.g. uuid() -> (uuid())
+//
+// 'literal' is true if the value is the text of a literal without its quotes, the way MySQL reports defaults.
+// NULL default returns an invalid string
+func NormalizeDefault(columnType, value string, literal bool) (sql.NullString, error) {
+	t := ParseColumnType(columnType)
+	kind := defaultKinds[t.Base]
+
+	value = strings.TrimSpace(value)
+	if literal {
+		if kind != DATE_DEFAULT || !timeKeywordRegexp.MatchString(value) {
+			value = quote(value)
+		}
+	} else if strings.EqualFold(value, "NULL") {
+		return sql.NullString{}, nil
+	} else if value == "" && kind == STRING_DEFAULT {
+		value = "''"
+	}
+	if value == "" {
+		return sql.NullString{}, fmt.Errorf("empty default value for %s", columnType)
+	}
+
+	// Casts of literals are dropped. e.g. '0'::numeric -> '0'
+	for match := castRegexp.FindStringSubmatch(value); match != nil; match = castRegexp.FindStringSubmatch(value) {
+		head := strings.TrimSpace(match[1])
+		if _, ok := unquote(head); !ok && !numberRegexp.MatchString(head) {
+			break
+		}
+		value = head
+	}
+
+	normalized, err := normalizeDefault(kind, t, value)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("invalid default value %s for %s: %w", value, columnType, err)
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
+}
+
+func normalizeDefault(kind defaultKind, t ColumnType, value string) (string, error) {
+	if match := timeKeywordRegexp.FindStringSubmatch(value); match != nil {
+		keyword := strings.ToUpper(match[1])
+		if keyword == "NOW" {
+			keyword = "CURRENT_TIMESTAMP"
+		}
+		if match[3] != "" {
+			keyword += "(" + match[3] + ")"
+		}
+		return keyword, nil
+	}
+
+	text, quoted := unquote(value)
+	switch {
+	case quoted:
+	case numberRegexp.MatchString(value), strings.EqualFold(value, "true"), strings.EqualFold(value, "false"):
+		text = value
+	case strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"):
+		return value, nil
+	case strings.HasPrefix(value, "'"):
+		// More than a single literal. e.g. 'a' || 'b'
+		return "(" + value + ")", nil
+	case functionCallRegexp.MatchString(value):
+		return "(" + value + ")", nil
+	case kind == STRING_DEFAULT || kind == DATE_DEFAULT || kind == OTHER_DEFAULT:
+		// Unquoted text is a literal, the same way gorm quotes the default values of strings. e.g. default:hello
+		text = value
+	default:
+		return "", fmt.Errorf("not a literal or an expression")
+	}
+
+	switch kind {
+	case INTEGER_DEFAULT:
+		if b, err := strconv.ParseBool(strings.ToLower(text)); err == nil && !numberRegexp.MatchString(text) {
+			if b {
+				return "1", nil
+			}
+			return "0", nil
+		}
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			if _, err := strconv.ParseUint(text, 10, 64); err != nil {
+				return "", fmt.Errorf("not an integer")
+			}
+			return strings.TrimPrefix(text, "+"), nil
+		}
+		return strconv.FormatInt(n, 10), nil
+
+	case BOOLEAN_DEFAULT:
+		switch strings.ToLower(text) {
+		case "true", "t", "1", "yes", "y", "on":
+			return "true", nil
+		case "false", "f", "0", "no", "n", "off":
+			return "false", nil
+		}
+		return "", fmt.Errorf("not a boolean")
+
+	case NUMERIC_DEFAULT:
+		if !numberRegexp.MatchString(text) {
+			return "", fmt.Errorf("not a number")
+		}
+		return normalizeNumber(text), nil
+
+	case STRING_DEFAULT:
+		if t.Base == "enum" {
+			var options []string
+			for _, match := range enumValueRegexp.FindAllStringSubmatch(strings.Join(t.Args, ","), -1) {
+				options = append(options, strings.ReplaceAll(match[1], "''", "'"))
+			}
+			if !slices.Contains(options, text) {
+				return "", fmt.Errorf("not one of the enum values %s", strings.Join(t.Args, ","))
+			}
+		} else if t.Length != -1 && (t.Base == "char" || t.Base == "varchar" || t.Base == "character" || t.Base == "character varying") {
+			if utf8.RuneCountInString(text) > t.Length {
+				return "", fmt.Errorf("longer than %d characters", t.Length)
+			}
+		}
+		return quote(text), nil
+
+	case DATE_DEFAULT:
+		valid := false
+		switch {
+		case t.Base == "date":
+			valid = dateRegexp.MatchString(text)
+		case strings.HasPrefix(t.Base, "time") && !strings.HasPrefix(t.Base, "timestamp"):
+			valid = timeRegexp.MatchString(text)
+		default:
+			valid = dateTimeRegexp.MatchString(text)
+		}
+		if !valid {
+			return "", fmt.Errorf("not a %s literal", t.Base)
+		}
+		return quote(text), nil
+	}
+
+	if quoted {
+		return quote(text), nil
+	}
+	return text, nil
+}
+
+// Returns true if both defaults are the same. Expressions are compared case insensitively, string literals exactly
+func DefaultEquals(a, b sql.NullString) bool {
+	if a.Valid != b.Valid {
+		return false
+	}
+	if strings.HasPrefix(a.String, "'") || strings.HasPrefix(b.String, "'") {
+		return a.String == b.String
+	}
+	return strings.EqualFold(a.String, b.String)
+}
+
+// Returns the decimal number without its exponent, leading '+' and the zeros that don't change its value,
+// so precise decimals aren't rounded like they would be through float64. e.g. +012.50 -> 12.5, 1.5e3 -> 1500, .5 -> 0.5.
+// Number must match numberRegexp, exponents that are too large to write out are kept
+func normalizeNumber(text string) string {
+	negative := text[0] == '-'
+	text = strings.TrimLeft(text, "+-")
+
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(text), "e")
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	exp := 0
+	if exponent != "" {
+		var err error
+		if exp, err = strconv.Atoi(exponent); err != nil || exp > MAX_DEFAULT_EXPONENT || exp < -MAX_DEFAULT_EXPONENT {
+			return strings.ToLower(text)
+		}
+	}
+
+	// Moves the decimal point of the digits by the exponent
+	digits := intPart + fracPart
+	point := len(intPart) + exp
+	switch {
+	case point <= 0:
+		intPart, fracPart = "0", strings.Repeat("0", -point)+digits
+	case point >= len(digits):
+		intPart, fracPart = digits+strings.Repeat("0", point-len(digits)), ""
+	default:
+		intPart, fracPart = digits[:point], digits[point:]
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	number := intPart
+	if fracPart != "" {
+		number += "." + fracPart
+	}
+	if negative && number != "0" {
+		number = "-" + number
+	}
+	return number
+}
+
+// Returns the text of a single or double quoted literal. False if the value is not quoted
+func unquote(value string) (string, bool) {
+	if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] {
+		return "", false
+	}
+	q := value[0]
+	var sb strings.Builder
+	for i := 1; i < len(value)-1; i++ {
+		c := value[i]
+		switch {
+		case c == q && i+1 < len(value)-1 && value[i+1] == q:
+			i++
+		case c == q:
+			// Quote that is not escaped, value is not a single literal. e.g. 'a' || 'b'
+			return "", false
+		case c == '\\' && i+1 < len(value)-1 && (value[i+1] == q || value[i+1] == '\\'):
+			i++
+			c = value[i]
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), true
+}
+
+// Returns the value as a single quoted SQL string literal
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestNormalizeDefault(t *testing.T) {
+	tests := []struct {
+		columnType string
+		value      string
+		literal    bool
+		want       string
+	}{
+		{"decimal(20,2)", "12345678901234567.89", false, "12345678901234567.89"},
+		{"decimal(10,2)", "'+012.50'", false, "12.5"},
+		{"decimal(10,2)", "10.00", true, "10"},
+		{"numeric", "'0'::numeric", false, "0"},
+		{"numeric", "-0.00", false, "0"},
+		{"numeric", ".5", false, "0.5"},
+		{"double", "1.5E3", false, "1500"},
+		{"double", "25e-3", false, "0.025"},
+		{"int", "'5'", false, "5"},
+		{"tinyint(1)", "true", false, "1"},
+		{"varchar(255)", "hello", true, "'hello'"},
+		{"varchar(255)", `"a"`, false, "'a'"},
+		{"character varying", "'hello'::character varying", false, "'hello'"},
+		{"datetime", "now()", false, "CURRENT_TIMESTAMP"},
+		{"timestamp(3)", "current_timestamp(3)", true, "CURRENT_TIMESTAMP(3)"},
+	}
+
+	for _, test := range tests {
+		got, err := NormalizeDefault(test.columnType, test.value, test.literal)
+		if err != nil {
+			t.Errorf("NormalizeDefault(%q, %q) returned %v", test.columnType, test.value, err)
+			continue
+		}
+		if !got.Valid || got.String != test.want {
+			t.Errorf("NormalizeDefault(%q, %q) = %q, want %q", test.columnType, test.value, got.String, test.want)
+		}
+	}
+
+	for _, test := range []struct{ columnType, value string }{
+		{"decimal(10,2)", "'abc'"},
+		{"decimal(10,2)", "1.2.3"},
+		{"int", "1.5"},
+	} {
+		if _, err := NormalizeDefault(test.columnType, test.value, false); err == nil {
+			t.Errorf("NormalizeDefault(%q, %q) is normalized without an error", test.columnType, test.value)
+		}
+	}
+
+	if got, err := NormalizeDefault("int", "NULL", false); err != nil || got.Valid {
+		t.Errorf("NULL default is normalized to %v, %v", got, err)
+	}
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"slices"
+	"strings"
+)
+
+type IndexType string
+
+const (
+	BTREE_INDEX    IndexType = "BTREE"
+	HASH_INDEX     IndexType = "HASH"
+	FULLTEXT_INDEX IndexType = "FULLTEXT"
+	SPATIAL_INDEX  IndexType = "SPATIAL"
+)
+
+type IndexColumn struct {
+	Name     string
+	Priority int  // Columns of an index are ordered by priority, lower comes first
+	Desc     bool // Sort order of the column in the index
+	Length   int  // Prefix length of the column, 0 means the whole column is indexed
+}
+
+// Index is a non-constraint index of a table. Unique constraints are kept in 'Table.IndexToUniqueCols',
+// see 'Index.IsUniqueConstraint'
+type Index struct {
+	Name    string
+	Columns []IndexColumn
+	Unique  bool
+	Type    IndexType // Empty type is the default index type of the database, BTREE
+}
+
+// Returns the index with the given name. If not found returns nil
+func (t *Table) GetIndex(name string) *Index {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// Adds the column to the index keeping the columns ordered by priority.
+// Columns with the same priority keep the order they are added in
+func (i *Index) AddColumn(col IndexColumn) {
+	i.Columns = append(i.Columns, col)
+	slices.SortStableFunc(i.Columns, func(a, b IndexColumn) int {
+		return a.Priority - b.Priority
+	})
+}
+
+// Returns the column names of the index in order
+func (i *Index) ColumnNames() []string {
+	names := make([]string, 0, len(i.Columns))
+	for _, c := range i.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// returns true if indexes are same, false if not. Priorities are not compared, only the resulting order is
+func (i *Index) Equals(index Index) bool {
+	if i.Name != index.Name || i.Unique != index.Unique || i.indexType() != index.indexType() ||
+		len(i.Columns) != len(index.Columns) {
+		return false
+	}
+	for j, c := range i.Columns {
+		other := index.Columns[j]
+		if !strings.EqualFold(c.Name, other.Name) || c.Desc != other.Desc || c.Length != other.Length {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *Index) indexType() IndexType {
+	if i.Type == "" {
+		return BTREE_INDEX
+	}
+	return IndexType(strings.ToUpper(string(i.Type)))
+}
+
+// Returns true if the index only enforces uniqueness of its columns.
+// Such indexes are kept as unique constraints in 'Table.IndexToUniqueCols' instead of 'Table.Indexes'
+func (i *Index) IsUniqueConstraint() bool {
+	if !i.Unique || i.indexType() != BTREE_INDEX {
+		return false
+	}
+	return !slices.ContainsFunc(i.Columns, func(c IndexColumn) bool { return c.Desc || c.Length > 0 })
+}
+
+// Returns the names of the unique constraints of the table in sorted order, so generated queries don't depend on map order
+func (t *Table) UniqueIndexNames() []string {
+	names := make([]string, 0, len(t.IndexToUniqueCols))
+	for name := range t.IndexToUniqueCols {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Moves the indexes that are unique constraints into 'IndexToUniqueCols'
+func (t *Table) SplitUniqueIndexes() {
+	if t.IndexToUniqueCols == nil {
+		t.IndexToUniqueCols = make(map[string][]string)
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(index *Index) bool {
+		if !index.IsUniqueConstraint() {
+			return false
+		}
+		t.IndexToUniqueCols[index.Name] = index.ColumnNames()
+		return true
+	})
+}
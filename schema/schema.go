@@ -10,15 +10,21 @@ import (
 type ColumnOperation int
 
 const (
-	DROP_FOREIGN_KEY ColumnOperation = iota
+	DROP_CHECK ColumnOperation = iota
+	DROP_FOREIGN_KEY
 	DROP_UNIQUE_INDEX
+	DROP_INDEX
+	DROP_PRIMARY_KEY
 	DROP_COLUMN
 	RENAME_COLUMN
 	MODIFY_COLUMN
 	ADD_COLUMN
+	ADD_PRIMARY_KEY
 	UPDATE_FOREIGN_KEY
 	ADD_FOREIGN_KEY
 	ADD_UNIQUE_INDEX
+	ADD_INDEX
+	ADD_CHECK
 )
 
 type Key string
@@ -40,6 +46,7 @@ const (
 )
 
 type Reference struct {
+	Name                 string // Name of the foreign key constraint
 	TableName            string
 	ColumnName           string
 	ReferencedTableName  string
@@ -65,12 +72,23 @@ func NewReference(tableName string, columnName string, referencedTableName strin
 	}
 }
 
+// Check is a CHECK constraint of a table
+type Check struct {
+	Name       string
+	Expression string // Condition without the CHECK keyword. e.g. "age > 13"
+}
+
 type Table struct {
 	Name              string
 	Columns           []*Column
 	References        []Reference
-	IndexToUniqueCols map[string][]string // index name maps to list of column names
-	PrimaryCols       []string
+	IndexToUniqueCols map[string][]string // unique constraint name maps to list of column names
+	Indexes           []*Index
+	Checks            []Check
+	PrimaryCols       []string // Columns of the primary key constraint in their key order
+	PrimaryKeyName    string   // Name of the primary key constraint of database tables, empty if the dialect doesn't report it
+	Comment           string   // Comment of the database table, models have no comment
+	RenamedFrom       string   // Previous name of the table, set by the 'migrator.RenamedFrom' marker. Empty for database tables
 }
 
 type TablePair struct {
@@ -81,6 +99,8 @@ type TablePair struct {
 type Column struct {
 	TableName    string
 	Name         string
+	FieldName    string // Name of the struct field the column is parsed from, empty for database columns
+	RenamedFrom  string // Previous name of the column, set by the 'renamedFrom' tag. Empty for database columns
 	ColumnType   string
 	Null         string
 	PrimaryKey   bool
@@ -88,6 +108,7 @@ type Column struct {
 	UniqueIndex  bool
 	DefaultValue sql.NullString
 	Extra        string
+	Comment      string // Comment of the column, set by the 'comment' tag for models
 }
 
 type ColumnMigration struct {
@@ -117,47 +138,63 @@ func (t *Table) PrettyPrint() {
 	}
 }
 
-// maps the old name to the new name of the renamed column
-var renamedColumns = make(map[string]string)
-
 // Compares the caller table with the given dst table
-// and creates migrations to change caller table into given 'dst' table
-func (t *Table) CompareWith(dst *Table) []*ColumnMigration {
+// and creates migrations to change caller table into given 'dst' table.
+// Column types are compared by the given rules of the database engine, nil rules only ignore their spelling
+func (t *Table) CompareWith(dst *Table, rules TypeRules) []*ColumnMigration {
 	var migrations []*ColumnMigration
 
-	var droppedColumns []string
-	clear(renamedColumns)
+	renamed := t.renamedColumns(dst)
+
 	// Check for dropped columns
 	for _, col := range t.Columns {
-		if contains, _ := dst.HasColumn(col); !contains {
-			if col.PrimaryKey {
-				migrations = append(migrations, NewColumnMigration(*dst.GetPrimaryKeyColumn(), *col, RENAME_COLUMN))
-				renamedColumns[col.Name] = dst.GetPrimaryKeyColumn().Name
-				continue
-			} else {
-				migrations = append(migrations, NewColumnMigration(*col, nil, DROP_COLUMN))
-				droppedColumns = append(droppedColumns, col.Name)
-				continue
-			}
+		if newName, ok := renamed[col.Name]; ok {
+			migrations = append(migrations, NewColumnMigration(*dst.GetColumn(newName), *col, RENAME_COLUMN))
+			continue
+		}
+		if dst.GetColumn(col.Name) == nil {
+			migrations = append(migrations, NewColumnMigration(*col, nil, DROP_COLUMN))
 		}
 	}
 
 	// Check for new columns
 	for _, col := range dst.Columns {
-		if contains, i := t.HasColumn(col); !contains { // dst has this column but method caller not. So ADD_COLUMN
-			if !col.PrimaryKey {
-				migrations = append(migrations, NewColumnMigration(*col, nil, ADD_COLUMN))
-				continue
-			}
-		} else { // Both have this column, check if column is modified by any means. We need to check each column property. If all is same, skip
-			if !col.Equals(*t.Columns[i]) { // Columns are not equal
-				migrations = append(migrations, NewColumnMigration(*col, *t.Columns[i], MODIFY_COLUMN))
-				continue
-			}
+		i := slices.IndexFunc(t.Columns, func(c *Column) bool { return renamed[c.Name] == col.Name })
+		if i == -1 {
+			i = slices.IndexFunc(t.Columns, func(c *Column) bool { return c.Name == col.Name })
+		}
+		if i == -1 { // dst has this column but method caller not. So ADD_COLUMN
+			migrations = append(migrations, NewColumnMigration(*col, nil, ADD_COLUMN))
+			continue
+		}
+		// Both have this column, check if column is modified by any means. Renamed columns are compared after the rename
+		old := *t.Columns[i]
+		old.Name = col.Name
+		if !col.Equals(old, rules) {
+			migrations = append(migrations, NewColumnMigration(*col, old, MODIFY_COLUMN))
+		}
+	}
+
+	// Primary key is compared as a whole, a changed primary key is dropped before the columns change and added after them
+	oldKey := make([]string, 0, len(t.PrimaryCols))
+	for _, name := range t.PrimaryCols {
+		if newName, ok := renamed[name]; ok {
+			name = newName
 		}
+		oldKey = append(oldKey, name)
 	}
+	if !slices.Equal(oldKey, dst.PrimaryCols) {
+		if len(t.PrimaryCols) > 0 {
+			migrations = append(migrations, NewColumnMigration(t.GetPrimaryKeyColumns(), nil, DROP_PRIMARY_KEY))
+		}
+		if len(dst.PrimaryCols) > 0 {
+			migrations = append(migrations, NewColumnMigration(dst.GetPrimaryKeyColumns(), nil, ADD_PRIMARY_KEY))
+		}
+	}
+
 	var droppedIndexes, createdIndexes []string
-	for uIndex, uCols := range dst.IndexToUniqueCols {
+	for _, uIndex := range dst.UniqueIndexNames() {
+		uCols := dst.IndexToUniqueCols[uIndex]
 		if slices.Contains(createdIndexes, uIndex) {
 			continue
 		}
@@ -167,7 +204,8 @@ func (t *Table) CompareWith(dst *Table) []*ColumnMigration {
 		}
 	}
 
-	for uIndex, uCols := range t.IndexToUniqueCols {
+	for _, uIndex := range t.UniqueIndexNames() {
+		uCols := t.IndexToUniqueCols[uIndex]
 		if slices.Contains(droppedIndexes, uIndex) {
 			continue
 		}
@@ -177,6 +215,32 @@ func (t *Table) CompareWith(dst *Table) []*ColumnMigration {
 		}
 	}
 
+	// Check dropped indexes. Changed indexes are dropped and created again
+	for _, index := range t.Indexes {
+		if other := dst.GetIndex(index.Name); other == nil || !other.Equals(*index) {
+			migrations = append(migrations, NewColumnMigration(*index, nil, DROP_INDEX))
+		}
+	}
+
+	// Check new indexes
+	for _, index := range dst.Indexes {
+		if other := t.GetIndex(index.Name); other == nil || !other.Equals(*index) {
+			migrations = append(migrations, NewColumnMigration(*index, nil, ADD_INDEX))
+		}
+	}
+
+	// Checks are compared by name, since databases rewrite the expressions. e.g. "age > 13" is reported as "(`age` > 13)" by MySQL
+	for _, check := range t.Checks {
+		if !slices.ContainsFunc(dst.Checks, func(c Check) bool { return c.Name == check.Name }) {
+			migrations = append(migrations, NewColumnMigration(check, nil, DROP_CHECK))
+		}
+	}
+	for _, check := range dst.Checks {
+		if !slices.ContainsFunc(t.Checks, func(c Check) bool { return c.Name == check.Name }) {
+			migrations = append(migrations, NewColumnMigration(check, nil, ADD_CHECK))
+		}
+	}
+
 	// Check dropped foreign key
 	for _, r1 := range t.References {
 		// If everything except reference options are same. We don't delete or add new constraint just update the constraint option
@@ -207,9 +271,11 @@ func (t *Table) CompareWith(dst *Table) []*ColumnMigration {
 	return migrations
 }
 
-// TODO: handle composite primary keys
-// Returns primaryKey column. If not found returns nil
+// Returns the first column of the primary key, the one relations reference. If not found returns nil
 func (t *Table) GetPrimaryKeyColumn() *Column {
+	if len(t.PrimaryCols) > 0 {
+		return t.GetColumn(t.PrimaryCols[0])
+	}
 	for _, c := range t.Columns {
 		if c.PrimaryKey {
 			return c
@@ -218,17 +284,55 @@ func (t *Table) GetPrimaryKeyColumn() *Column {
 	return nil
 }
 
+// Returns the columns of the primary key in their key order
+func (t *Table) GetPrimaryKeyColumns() []Column {
+	var cols []Column
+	for _, name := range t.PrimaryCols {
+		if c := t.GetColumn(name); c != nil {
+			cols = append(cols, *c)
+		}
+	}
+	return cols
+}
+
 // Checks columns existance by name, returns its index if exists.
 // Returns -1 if column not found
 func (t *Table) HasColumn(col *Column) (contains bool, index int) {
 	for i, c := range t.Columns {
-		if ((c.Name == col.Name) || (renamedColumns[c.Name] == col.Name)) && (c.PrimaryKey == col.PrimaryKey) {
+		if c.Name == col.Name && c.PrimaryKey == col.PrimaryKey {
 			return true, i
 		}
 	}
 	return false, -1
 }
 
+// Returns the column with the given name. If not found returns nil
+func (t *Table) GetColumn(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Returns the 'map[oldName] -> newName' of the columns renamed by 'Column.RenamedFrom' between the caller and dst table.
+// The name of either table can be the previous one, so the down migration renames the columns back
+func (t *Table) renamedColumns(dst *Table) map[string]string {
+	renamed := make(map[string]string)
+	for _, col := range dst.Columns {
+		if col.RenamedFrom != "" && t.GetColumn(col.Name) == nil && t.GetColumn(col.RenamedFrom) != nil && dst.GetColumn(col.RenamedFrom) == nil {
+			renamed[col.RenamedFrom] = col.Name
+		}
+	}
+	for _, col := range t.Columns {
+		if col.RenamedFrom != "" && dst.GetColumn(col.Name) == nil && dst.GetColumn(col.RenamedFrom) != nil && t.GetColumn(col.RenamedFrom) == nil {
+			renamed[col.Name] = col.RenamedFrom
+		}
+	}
+	return renamed
+}
+
 func (c *Column) PrettyPrint() {
 	if !c.PrimaryKey {
 		if c.UniqueIndex {
@@ -251,13 +355,14 @@ func (c *Column) PrettyPrint() {
 	}
 }
 
-// returns true if columns are same, false if not
-func (c *Column) Equals(col Column) bool {
+// returns true if columns are same, false if not. Column types are compared by the given rules
+func (c *Column) Equals(col Column, rules TypeRules) bool {
 	if strings.ToLower(c.Name) != strings.ToLower(col.Name) ||
-		strings.ToLower(c.ColumnType) != strings.ToLower(col.ColumnType) ||
+		!SameColumnType(c.ColumnType, col.ColumnType, rules) ||
 		strings.ToLower(c.Null) != strings.ToLower(col.Null) ||
 		strings.ToLower(c.Extra) != strings.ToLower(col.Extra) ||
-		(c.DefaultValue.Valid != col.DefaultValue.Valid && c.DefaultValue.String != col.DefaultValue.String) {
+		!DefaultEquals(c.DefaultValue, col.DefaultValue) ||
+		c.Comment != col.Comment {
 		return false
 	}
 	return true
@@ -0,0 +1,78 @@
+package migrator
+
+import (
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Marker that makes a table unmanaged when it is found in the comment of the table.
+// e.g. "ALTER TABLE audit_logs COMMENT = 'migrator:unmanaged'"
+const UNMANAGED_TABLE_MARKER = "migrator:unmanaged"
+
+// Returns true if the table with the given comment is managed by migrator.
+// See 'Migrator.IncludeTables', 'Migrator.ExcludeTables' and 'UNMANAGED_TABLE_MARKER'
+func (m *Migrator) isManaged(name, comment string) (bool, error) {
+	if name == VERSION_TABLE || strings.Contains(comment, UNMANAGED_TABLE_MARKER) {
+		return false, nil
+	}
+
+	if len(m.IncludeTables) > 0 {
+		included, err := matchTable(m.IncludeTables, name)
+		if err != nil || !included {
+			return false, err
+		}
+	}
+	excluded, err := matchTable(m.ExcludeTables, name)
+	return !excluded, err
+}
+
+// Returns true if the table name matches any of the glob patterns. e.g. "audit_*"
+func matchTable(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid table pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns the managed tables and the names of the unmanaged tables
+func (m *Migrator) splitManagedTables(tables []*schema.Table) ([]*schema.Table, []string, error) {
+	var managed []*schema.Table
+	var unmanaged []string
+	for _, t := range tables {
+		ok, err := m.isManaged(t.Name, t.Comment)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			managed = append(managed, t)
+		} else {
+			unmanaged = append(unmanaged, t.Name)
+		}
+	}
+	return managed, unmanaged, nil
+}
+
+// Returns the model tables that are managed. Models of unmanaged tables are only used as foreign key targets,
+// they are never created, altered or dropped. 'unmanaged' are the names of the unmanaged tables in the database
+func (m *Migrator) managedModels(tables []*schema.Table, unmanaged []string) ([]*schema.Table, error) {
+	var managed []*schema.Table
+	for _, t := range tables {
+		ok, err := m.isManaged(t.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if ok && !slices.Contains(unmanaged, t.Name) {
+			managed = append(managed, t)
+		}
+	}
+	return managed, nil
+}
@@ -0,0 +1,48 @@
+package migrator
+
+import (
+	"testing"
+)
+
+func TestIsManaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		table   string
+		comment string
+		want    bool
+	}{
+		{"no patterns", nil, nil, "users", "", true},
+		{"version table", nil, nil, VERSION_TABLE, "", false},
+		{"unmanaged comment", nil, nil, "audit_logs", "shared with billing, migrator:unmanaged", false},
+		{"included by name", []string{"users"}, nil, "users", "", true},
+		{"not included", []string{"users"}, nil, "posts", "", false},
+		{"included by glob", []string{"app_*"}, nil, "app_users", "", true},
+		{"glob matches the whole name", []string{"app_*"}, nil, "my_app_users", "", false},
+		{"single character glob", []string{"log_?"}, nil, "log_1", "", true},
+		{"character class", []string{"shard_[0-9]"}, nil, "shard_a", "", false},
+		{"excluded by glob", nil, []string{"tmp_*"}, "tmp_import", "", false},
+		{"exclude wins over include", []string{"app_*"}, []string{"app_cache"}, "app_cache", "", false},
+		{"included and not excluded", []string{"app_*"}, []string{"app_cache"}, "app_users", "", true},
+		{"included but unmanaged", []string{"app_*"}, nil, "app_users", UNMANAGED_TABLE_MARKER, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &Migrator{IncludeTables: test.include, ExcludeTables: test.exclude}
+			got, err := m.isManaged(test.table, test.comment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("isManaged(%q, %q) = %v, want %v", test.table, test.comment, got, test.want)
+			}
+		})
+	}
+
+	m := &Migrator{ExcludeTables: []string{"app_["}}
+	if _, err := m.isManaged("app_users", ""); err == nil {
+		t.Error("invalid table pattern is matched without an error")
+	}
+}
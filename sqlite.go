@@ -0,0 +1,574 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/AkifSahn/migrator/ddl"
+	"github.com/AkifSahn/migrator/schema"
+	"github.com/AkifSahn/migrator/utils"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SQLiteDialect generates SQLite compatible migrations.
+// SQLite cannot modify columns or foreign keys in place, tables that require it are rebuilt.
+// The driver must be imported by the caller, "github.com/mattn/go-sqlite3" is used unless Driver is set. e.g. "sqlite"
+type SQLiteDialect struct {
+	Driver string
+}
+
+// Written before the statements that turn the foreign key checks off. SQLite ignores 'PRAGMA foreign_keys' in a transaction,
+// so a rebuild in a transaction fires the ON DELETE actions of the dropped table and deletes the referencing rows
+const SQLITE_NO_TRANSACTION_NOTE = "-- Must run outside of a transaction, sqlite ignores PRAGMA foreign_keys in one. e.g. x-no-tx-wrap=true for golang-migrate\n"
+
+var (
+	_ Dialect        = SQLiteDialect{}
+	_ TableRebuilder = SQLiteDialect{}
+)
+
+func (SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+func (d SQLiteDialect) DriverName() string {
+	if d.Driver != "" {
+		return d.Driver
+	}
+	return "sqlite3"
+}
+
+func (SQLiteDialect) DataType(goType string) (string, error) {
+	return utils.ToSqliteDataType(goType)
+}
+
+// SQLite reports the column types as they are declared, drivers read the values by the declared type.
+// Only the spelling of the type is ignored
+func (SQLiteDialect) CanonicalType(t schema.ColumnType) schema.ColumnType {
+	return t
+}
+
+// Same table and index golang-migrate creates for sqlite3
+func (SQLiteDialect) CreateVersionTableQuery() string {
+	return "CREATE TABLE IF NOT EXISTS schema_migrations (version uint64, dirty bool);\n" +
+		"CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON schema_migrations (version);\n"
+}
+
+func (SQLiteDialect) InsertVersionQuery() string {
+	return "INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)"
+}
+
+func (SQLiteDialect) SplitStatements(script string) ([]string, error) {
+	return ddl.SplitSQLiteStatements(script)
+}
+
+// SQLite has a single schema per connection, schemaName is ignored by the introspection
+func (SQLiteDialect) TableNames(ctx context.Context, db *sql.DB, schemaName string) ([]string, error) {
+	query := "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, &IntrospectionError{Query: query, Err: err}
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Query: query, Err: err}
+	}
+
+	return names, nil
+}
+
+// SQLite has no table comments. The SQL comments written in the CREATE TABLE statement of the table are returned instead,
+// one comment per line. e.g. "-- migrator:unmanaged"
+func (SQLiteDialect) TableComments(ctx context.Context, db *sql.DB, schemaName string) (map[string]string, error) {
+	query := "SELECT name, sql FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND sql IS NOT NULL"
+	definitions, err := queryTableComments(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string]string)
+	for name, definition := range definitions {
+		if comment := sqlComments(definition); comment != "" {
+			comments[name] = comment
+		}
+	}
+	return comments, nil
+}
+
+// Returns the '--' and '/* */' comments of the SQLite statement, one per line.
+// Strings and quoted names are skipped, so their text is never taken as a comment
+func sqlComments(statement string) string {
+	var comments []string
+	for i := 0; i < len(statement); i++ {
+		switch c := statement[i]; {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			// Quotes are escaped by doubling them, which is the same as closing and opening the quote again
+			if end := strings.IndexByte(statement[i+1:], closing); end != -1 {
+				i += end + 1
+			} else {
+				i = len(statement)
+			}
+		case strings.HasPrefix(statement[i:], "--"):
+			end := strings.IndexByte(statement[i:], '\n')
+			if end == -1 {
+				end = len(statement) - i
+			}
+			comments = append(comments, strings.TrimSpace(statement[i:i+end]))
+			i += end
+		case strings.HasPrefix(statement[i:], "/*"):
+			end := strings.Index(statement[i+2:], "*/")
+			if end == -1 {
+				end = len(statement) - i - 2
+			}
+			comments = append(comments, strings.TrimSpace(statement[i:min(i+end+4, len(statement))]))
+			i += end + 3
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+func (SQLiteDialect) DescribeTable(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Column, error) {
+	query := fmt.Sprintf("PRAGMA table_info(\"%s\")", tableName)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var cols []*schema.Column
+	for rows.Next() {
+		var col schema.Column
+		var cid, notNull, pk int
+		col.TableName = tableName
+
+		if err := rows.Scan(&cid, &col.Name, &col.ColumnType, &notNull, &col.DefaultValue, &pk); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		col.Null = "YES"
+		if notNull == 1 || pk > 0 {
+			col.Null = "NO"
+		}
+		col.PrimaryKey = pk > 0
+		col.DefaultValue = introspectedDefault(col.ColumnType, col.DefaultValue, false)
+
+		cols = append(cols, &col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	rows.Close()
+
+	// AUTOINCREMENT is only visible in the table definition
+	query = "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?"
+	var definition string
+	if err := db.QueryRowContext(ctx, query, tableName).Scan(&definition); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	if strings.Contains(strings.ToUpper(definition), "AUTOINCREMENT") {
+		for _, col := range cols {
+			if col.PrimaryKey {
+				col.Extra = "auto_increment"
+			}
+		}
+	}
+
+	return cols, nil
+}
+
+// 'pk' of the table info is the position of the column in the primary key, 0 if the column is not a key column
+func (SQLiteDialect) GetPrimaryKey(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]string, error) {
+	query := "SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk"
+	return queryPrimaryKey(ctx, db, tableName, query, tableName)
+}
+
+func (SQLiteDialect) GetReferences(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Reference, error) {
+	query := fmt.Sprintf("PRAGMA foreign_key_list(\"%s\")", tableName)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var references []schema.Reference
+	for rows.Next() {
+		var id, seq int
+		var match string
+		reference := schema.Reference{TableName: tableName}
+		err := rows.Scan(&id, &seq, &reference.ReferencedTableName, &reference.ColumnName, &reference.ReferencedColumnName,
+			&reference.UpdateOption, &reference.DeleteOption, &match)
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		references = append(references, reference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	return references, nil
+}
+
+func (d SQLiteDialect) GetUniqueIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) (map[string][]string, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.IndexToUniqueCols, nil
+}
+
+func (d SQLiteDialect) GetIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	table := schema.Table{Name: tableName}
+	var err error
+	if table.Indexes, err = d.indexes(ctx, db, schemaName, tableName); err != nil {
+		return nil, err
+	}
+	table.SplitUniqueIndexes()
+	return table.Indexes, nil
+}
+
+// Returns the indexes of the table that are created by CREATE INDEX.
+// Unique constraints are created that way as well, since SQLite generates names for the inline ones
+func (SQLiteDialect) indexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*schema.Index, error) {
+	query := fmt.Sprintf("PRAGMA index_list(\"%s\")", tableName)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	defer rows.Close()
+
+	var indexes []*schema.Index
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+		if origin == "c" {
+			indexes = append(indexes, &schema.Index{Name: name, Unique: unique == 1})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+	rows.Close()
+
+	slices.SortFunc(indexes, func(a, b *schema.Index) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, index := range indexes {
+		query := fmt.Sprintf("PRAGMA index_xinfo(\"%s\")", index.Name)
+		infoRows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+
+		for infoRows.Next() {
+			var seqNo, cid, desc, key int
+			var columnName, collation sql.NullString
+			if err := infoRows.Scan(&seqNo, &cid, &columnName, &desc, &collation, &key); err != nil {
+				infoRows.Close()
+				return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+			}
+			// Auxiliary columns such as rowid are not part of the index key
+			if key == 1 {
+				index.Columns = append(index.Columns, schema.IndexColumn{Name: columnName.String, Priority: seqNo, Desc: desc == 1})
+			}
+		}
+		err = infoRows.Err()
+		infoRows.Close()
+		if err != nil {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+		}
+	}
+
+	return indexes, nil
+}
+
+// Start of a named check constraint in a CREATE TABLE statement. e.g. CONSTRAINT "chk_users_age" CHECK (
+var sqliteCheckRegexp = regexp.MustCompile(`(?i)\bCONSTRAINT\s+("[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\]|\w+)\s+CHECK\s*\(`)
+
+// SQLite only keeps the check constraints in the CREATE TABLE statement, so they are parsed from it.
+// Unnamed checks are skipped, since they can't be told apart
+func (SQLiteDialect) GetChecks(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]schema.Check, error) {
+	query := "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?"
+	var definition string
+	if err := db.QueryRowContext(ctx, query, tableName).Scan(&definition); err != nil {
+		return nil, &IntrospectionError{Table: tableName, Query: query, Err: err}
+	}
+
+	var checks []schema.Check
+	for _, match := range sqliteCheckRegexp.FindAllStringSubmatchIndex(definition, -1) {
+		start := match[1] - 1
+		end := schema.ClosingParenthesis(definition, start)
+		if end == -1 {
+			return nil, &IntrospectionError{Table: tableName, Query: query, Err: fmt.Errorf("unbalanced check constraint")}
+		}
+		name := definition[match[2]:match[3]]
+		checks = append(checks, schema.Check{
+			Name:       strings.Trim(name, "\"`[]"),
+			Expression: strings.TrimSpace(definition[start+1 : end]),
+		})
+	}
+	return checks, nil
+}
+
+// SQLite only allows AUTOINCREMENT on an 'integer primary key' column, so it is declared on the column
+func (SQLiteDialect) columnDefinition(c schema.Column) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", c.Name, c.ColumnType))
+
+	if strings.Contains(c.Extra, "auto_increment") {
+		sb.WriteString(" PRIMARY KEY AUTOINCREMENT")
+	} else if c.Null == "NO" {
+		sb.WriteString(" NOT NULL")
+	}
+	if c.DefaultValue.Valid {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(c.DefaultValue.String)
+	}
+	return sb.String()
+}
+
+// Returns the CREATE TABLE query of 't' under the given name, without its indexes
+func (d SQLiteDialect) createTableStatement(t *schema.Table, name string) (string, error) {
+	var defs []string
+	hasAutoIncrement := false
+	for _, c := range t.Columns {
+		if strings.Contains(c.Extra, "auto_increment") {
+			if !strings.EqualFold(c.ColumnType, "integer") || len(t.PrimaryCols) != 1 || !c.PrimaryKey {
+				return "", &UnsupportedOperationError{Operation: "CREATE TABLE", Table: t.Name, Column: c.Name,
+					Reason: "auto increment is only allowed on a single 'integer' primary key column"}
+			}
+			hasAutoIncrement = true
+		}
+		defs = append(defs, d.columnDefinition(*c))
+	}
+
+	if !hasAutoIncrement && len(t.PrimaryCols) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(t.PrimaryCols, ", ")))
+	}
+
+	for _, reference := range t.References {
+		// SQLite doesn't report the names of the foreign keys, introspected ones are created without a name
+		constraint := ""
+		if reference.Name != "" {
+			constraint = fmt.Sprintf("CONSTRAINT \"%s\" ", reference.Name)
+		}
+		defs = append(defs, fmt.Sprintf("%sFOREIGN KEY (%s) REFERENCES %s(%s) ON DELETE %s ON UPDATE %s",
+			constraint, reference.ColumnName,
+			reference.ReferencedTableName, reference.ReferencedColumnName, reference.DeleteOption, reference.UpdateOption))
+	}
+
+	for _, check := range t.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT \"%s\" CHECK (%s)", check.Name, check.Expression))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);\n", name, strings.Join(defs, ",\n\t")), nil
+}
+
+// Unique constraints are created as named indexes, since SQLite generates names for the inline ones.
+// Other indexes of the table are created after them
+func (d SQLiteDialect) createIndexStatements(t *schema.Table) (string, error) {
+	var sb strings.Builder
+
+	for _, iName := range t.UniqueIndexNames() {
+		query, err := d.AddUniqueIndexQuery(*t, iName, t.IndexToUniqueCols[iName])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(query)
+	}
+
+	for _, index := range t.Indexes {
+		query, err := d.AddIndexQuery(*t, *index)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(query)
+	}
+	return sb.String(), nil
+}
+
+func (SQLiteDialect) DropTableQuery(t *schema.Table) (string, error) {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", t.Name), nil
+}
+
+func (d SQLiteDialect) CreateTableQuery(t *schema.Table) (string, error) {
+	query, err := d.createTableStatement(t, t.Name)
+	if err != nil {
+		return "", err
+	}
+	indexes, err := d.createIndexStatements(t)
+	if err != nil {
+		return "", err
+	}
+	return query + indexes, nil
+}
+
+func (d SQLiteDialect) AddColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	if c.PrimaryKey {
+		return "", &UnsupportedOperationError{Operation: "ADD COLUMN", Table: t.Name, Column: c.Name, Reason: "sqlite cannot add a primary key column, the table must be rebuilt"}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s\n\tADD COLUMN %s;\n", t.Name, d.columnDefinition(c)), nil
+}
+
+func (SQLiteDialect) DropColumnQuery(t schema.Table, c schema.Column) (string, error) {
+	if c.PrimaryKey {
+		return "", &UnsupportedOperationError{Operation: "DROP COLUMN", Table: t.Name, Column: c.Name, Reason: "cannot drop a primary key column"}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s\n\tDROP COLUMN %s;\n", t.Name, c.Name), nil
+}
+
+func (SQLiteDialect) ModifyColumnQuery(t schema.Table, c schema.Column, old schema.Column) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "MODIFY COLUMN", Table: t.Name, Column: c.Name, Reason: "sqlite cannot modify a column, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) RenameColumnQuery(t schema.Table, newCol schema.Column, oldColumn schema.Column) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tRENAME COLUMN %s TO %s;\n", t.Name, oldColumn.Name, newCol.Name), nil
+}
+
+func (SQLiteDialect) RenameTableQuery(oldName, newName string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s\n\tRENAME TO %s;\n", oldName, newName), nil
+}
+
+func (SQLiteDialect) AddReferenceQuery(reference schema.Reference) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "ADD FOREIGN KEY", Table: reference.TableName, Column: reference.ColumnName, Reason: "sqlite cannot add a foreign key, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) DropReferenceQuery(reference schema.Reference) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "DROP FOREIGN KEY", Table: reference.TableName, Column: reference.ColumnName, Reason: "sqlite cannot drop a foreign key, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) AddUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	return fmt.Sprintf("CREATE UNIQUE INDEX \"%s\" ON %s (%s);\n", indexName, table.Name, strings.Join(colNames, ", ")), nil
+}
+
+func (SQLiteDialect) DropUniqueIndexQuery(table schema.Table, indexName string, colNames []string) (string, error) {
+	return fmt.Sprintf("DROP INDEX IF EXISTS \"%s\";\n", indexName), nil
+}
+
+// SQLite has a single index type and no prefix indexes
+func (SQLiteDialect) AddIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	if index.Type != "" && index.Type != schema.BTREE_INDEX {
+		return "", &UnsupportedOperationError{Operation: "CREATE INDEX", Table: table.Name, Reason: fmt.Sprintf("sqlite has no %s index", index.Type)}
+	}
+
+	var cols []string
+	for _, col := range index.Columns {
+		if col.Length > 0 {
+			return "", &UnsupportedOperationError{Operation: "CREATE INDEX", Table: table.Name, Column: col.Name, Reason: "sqlite does not support index prefix length"}
+		}
+		if col.Desc {
+			cols = append(cols, col.Name+" DESC")
+		} else {
+			cols = append(cols, col.Name)
+		}
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX \"%s\" ON %s (%s);\n", unique, index.Name, table.Name, strings.Join(cols, ", ")), nil
+}
+
+func (SQLiteDialect) DropIndexQuery(table schema.Table, index schema.Index) (string, error) {
+	return fmt.Sprintf("DROP INDEX IF EXISTS \"%s\";\n", index.Name), nil
+}
+
+func (SQLiteDialect) AddCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "ADD CHECK", Table: table.Name, Reason: "sqlite cannot add a check constraint, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) DropCheckQuery(table schema.Table, check schema.Check) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "DROP CHECK", Table: table.Name, Reason: "sqlite cannot drop a check constraint, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) AddPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "ADD PRIMARY KEY", Table: table.Name, Reason: "sqlite cannot change a primary key, the table must be rebuilt"}
+}
+
+func (SQLiteDialect) DropPrimaryKeyQuery(table schema.Table, cols []schema.Column) (string, error) {
+	return "", &UnsupportedOperationError{Operation: "DROP PRIMARY KEY", Table: table.Name, Reason: "sqlite cannot change a primary key, the table must be rebuilt"}
+}
+
+// Column, primary key, foreign key and check constraint changes can't be done by ALTER TABLE in SQLite.
+// Columns that take part in a key or can't be added with their constraints are handled by a rebuild as well
+func (SQLiteDialect) RequiresRebuild(migration *schema.ColumnMigration) bool {
+	switch migration.Operation {
+	case schema.MODIFY_COLUMN, schema.ADD_FOREIGN_KEY, schema.DROP_FOREIGN_KEY, schema.UPDATE_FOREIGN_KEY,
+		schema.ADD_PRIMARY_KEY, schema.DROP_PRIMARY_KEY, schema.ADD_CHECK, schema.DROP_CHECK:
+		return true
+	case schema.ADD_COLUMN:
+		c := migration.ApplyOn.(schema.Column)
+		return c.PrimaryKey || (c.Null == "NO" && !c.DefaultValue.Valid)
+	case schema.DROP_COLUMN:
+		c := migration.ApplyOn.(schema.Column)
+		return c.PrimaryKey || c.ForeignKey
+	}
+	return false
+}
+
+// Follows the table rebuild procedure described in https://www.sqlite.org/lang_altertable.html#otheralter
+func (d SQLiteDialect) RebuildTableQuery(from *schema.Table, to *schema.Table, renamed map[string]string) (string, error) {
+	tempName := "new_" + to.Name
+
+	create, err := d.createTableStatement(to, tempName)
+	if err != nil {
+		return "", err
+	}
+	indexes, err := d.createIndexStatements(to)
+	if err != nil {
+		return "", err
+	}
+
+	// Copy the columns that exist in both tables, renamed columns are copied from their old names
+	var dstCols, srcCols []string
+	for _, c := range to.Columns {
+		srcName := c.Name
+		for oldName, newName := range renamed {
+			if newName == c.Name {
+				srcName = oldName
+			}
+		}
+		if slices.ContainsFunc(from.Columns, func(fc *schema.Column) bool { return fc.Name == srcName }) {
+			dstCols = append(dstCols, c.Name)
+			srcCols = append(srcCols, srcName)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n-- Rebuilding %s, since sqlite cannot alter it in place\n", to.Name))
+	sb.WriteString(SQLITE_NO_TRANSACTION_NOTE)
+	sb.WriteString("PRAGMA foreign_keys = OFF;\n")
+	sb.WriteString(create)
+	if len(dstCols) > 0 {
+		sb.WriteString(fmt.Sprintf("INSERT INTO %s (%s)\n\tSELECT %s FROM %s;\n",
+			tempName, strings.Join(dstCols, ", "), strings.Join(srcCols, ", "), from.Name))
+	}
+	sb.WriteString(fmt.Sprintf("DROP TABLE %s;\n", from.Name))
+	sb.WriteString(fmt.Sprintf("ALTER TABLE %s RENAME TO %s;\n", tempName, to.Name))
+	sb.WriteString(indexes)
+	sb.WriteString("PRAGMA foreign_key_check;\n")
+	sb.WriteString("PRAGMA foreign_keys = ON;\n")
+	return sb.String(), nil
+}
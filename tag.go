@@ -0,0 +1,143 @@
+package migrator
+
+import (
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A setting of a gorm tag. e.g. "SIZE" and "255" of `gorm:"size:255"`
+type tagSetting struct {
+	Key   string // Upper cased key, so keys are matched case insensitively. e.g. "PRIMARYKEY"
+	Value string // Everything after the first ':', empty if the setting has no value
+}
+
+// Name of a named check constraint, same as gorm. e.g. "chk_age" of "chk_age,age > 13"
+var checkNameRegexp = regexp.MustCompile(`^[\w-]+$`)
+
+// Splits the gorm tag into its settings by the rules of gorm's 'schema.ParseTagSetting'.
+// Settings are separated by ';' and the key ends at the first ':', both can be escaped by '\'.
+// e.g. `comment:a\;b;default:'12:00'` -> COMMENT "a;b", DEFAULT "'12:00'". Settings without a key are skipped
+func parseTagSettings(tag string) []tagSetting {
+	var settings []tagSetting
+	var key, value strings.Builder
+	inValue := false
+
+	flush := func() {
+		if k := strings.ToUpper(strings.TrimSpace(key.String())); k != "" {
+			settings = append(settings, tagSetting{Key: k, Value: value.String()})
+		}
+		key.Reset()
+		value.Reset()
+		inValue = false
+	}
+
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case c == '\\' && i+1 < len(tag) && (tag[i+1] == ';' || tag[i+1] == ':'):
+			i++
+			c = tag[i]
+		case c == ';':
+			flush()
+			continue
+		case c == ':' && !inValue:
+			inValue = true
+			continue
+		}
+
+		if inValue {
+			value.WriteByte(c)
+		} else {
+			key.WriteByte(c)
+		}
+	}
+	flush()
+
+	return settings
+}
+
+// Returns false only for the "false" value, so a setting without a value is true. e.g. "autoIncrement", "autoIncrement:false"
+func tagBool(value string) bool {
+	return !strings.EqualFold(strings.TrimSpace(value), "false")
+}
+
+// Returns the value of a numeric setting. e.g. "size:255"
+func tagInt(setting tagSetting) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(setting.Value))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", strings.ToLower(setting.Key), setting.Value)
+	}
+	return n, nil
+}
+
+// Parses the value of the 'constraint' setting. e.g. "OnUpdate:CASCADE,OnDelete:SET NULL"
+func parseConstraintTag(value string, deleteOption, updateOption *schema.ReferenceOption) error {
+	for _, option := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(option, ":")
+		referenceOption := schema.ReferenceOption(strings.ToUpper(strings.Join(strings.Fields(val), " ")))
+
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "":
+			continue
+		case "ONDELETE":
+			*deleteOption = referenceOption
+		case "ONUPDATE":
+			*updateOption = referenceOption
+		default:
+			return fmt.Errorf("constraint option %q is not supported", key)
+		}
+
+		if !slices.Contains([]schema.ReferenceOption{schema.RESTRICT_OPTION, schema.CASCADE_OPTION, schema.SET_NULL_OPTION,
+			schema.NO_ACTION_OPTION, schema.SET_DEFAULT_OPTION}, referenceOption) {
+
+			return fmt.Errorf("invalid reference option %q", val)
+		}
+	}
+	return nil
+}
+
+// Parses the value of the 'check' setting into a check constraint, the same way gorm does.
+// e.g. "age > 13" is named by 'NamingStrategy.CheckName', "chk_age,age > 13" is named "chk_age"
+func (m *Migrator) parseCheckTag(table *schema.Table, fieldName, value string) (schema.Check, error) {
+	check := schema.Check{Name: m.namer().CheckName(table.Name, fieldName), Expression: value}
+	if name, expression, found := strings.Cut(value, ","); found && checkNameRegexp.MatchString(name) {
+		check = schema.Check{Name: name, Expression: expression}
+	}
+
+	check.Expression = strings.TrimSpace(check.Expression)
+	if check.Expression == "" {
+		return schema.Check{}, fmt.Errorf("check constraint %s has no expression", check.Name)
+	}
+	return check, nil
+}
+
+// Applies the 'size', 'precision' and 'scale' settings to the column type of the go type, the way gorm does:
+//   - size is the length of string columns, text columns become varchar. e.g. size:100 -> varchar(100)
+//   - precision and scale turn number columns into decimal. e.g. precision:10;scale:2 -> decimal(10,2)
+//   - precision is the fractional seconds of time columns. e.g. precision:3 -> datetime(3)
+//
+// Settings that don't apply to the column type are ignored. 0 means the setting is not given
+func sizedColumnType(columnType string, size, precision, scale int) string {
+	t := schema.ParseColumnType(columnType)
+
+	switch {
+	case t.Base == "text" && size > 0:
+		t.Base = "varchar"
+		t.Length = size
+	case slices.Contains([]string{"varchar", "char", "character varying", "character", "varbinary", "binary"}, t.Base) && size > 0:
+		t.Length = size
+	case slices.Contains([]string{"decimal", "numeric", "float", "double", "double precision", "real"}, t.Base) && precision > 0:
+		t.Base = "decimal"
+		t.Precision = precision
+		t.Scale = scale
+	case (strings.HasPrefix(t.Base, "time") || t.Base == "datetime") && precision > 0:
+		t.Length = precision
+	default:
+		return columnType
+	}
+	return t.String()
+}
@@ -0,0 +1,33 @@
+package migrator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTagSettings(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []tagSetting
+	}{
+		{"", nil},
+		{"primaryKey", []tagSetting{{Key: "PRIMARYKEY"}}},
+		{"column:user_name;size:100", []tagSetting{{Key: "COLUMN", Value: "user_name"}, {Key: "SIZE", Value: "100"}}},
+		{" not null ; unique ", []tagSetting{{Key: "NOT NULL"}, {Key: "UNIQUE"}}},
+		{"default:'12:00'", []tagSetting{{Key: "DEFAULT", Value: "'12:00'"}}},
+		{`comment:a\;b`, []tagSetting{{Key: "COMMENT", Value: "a;b"}}},
+		{`comment:a\:b`, []tagSetting{{Key: "COMMENT", Value: "a:b"}}},
+		{`col\:umn:name`, []tagSetting{{Key: "COL:UMN", Value: "name"}}},
+		{`comment:a\b`, []tagSetting{{Key: "COMMENT", Value: `a\b`}}},
+		{"constraint:OnUpdate:CASCADE,OnDelete:SET NULL", []tagSetting{{Key: "CONSTRAINT", Value: "OnUpdate:CASCADE,OnDelete:SET NULL"}}},
+		{"autoIncrement:false", []tagSetting{{Key: "AUTOINCREMENT", Value: "false"}}},
+		{";;index;:ignored;", []tagSetting{{Key: "INDEX"}}},
+		{"check:age > 13;comment:", []tagSetting{{Key: "CHECK", Value: "age > 13"}, {Key: "COMMENT"}}},
+	}
+
+	for _, test := range tests {
+		if got := parseTagSettings(test.tag); !slices.Equal(got, test.want) {
+			t.Errorf("parseTagSettings(%q) = %q, want %q", test.tag, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Pluralization rules of github.com/jinzhu/inflection, which gorm uses for table names.
+// Later rules take precedence over the earlier ones
+var pluralRules = [][2]string{
+	{"([a-z])$", "${1}s"},
+	{"s$", "s"},
+	{"^(ax|test)is$", "${1}es"},
+	{"(octop|vir)us$", "${1}i"},
+	{"(octop|vir)i$", "${1}i"},
+	{"(alias|status|campus)$", "${1}es"},
+	{"(bu)s$", "${1}ses"},
+	{"(buffal|tomat)o$", "${1}oes"},
+	{"([ti])um$", "${1}a"},
+	{"([ti])a$", "${1}a"},
+	{"sis$", "ses"},
+	{"(?:([^f])fe|([lr])f)$", "${1}${2}ves"},
+	{"(hive)$", "${1}s"},
+	{"([^aeiouy]|qu)y$", "${1}ies"},
+	{"(x|ch|ss|sh)$", "${1}es"},
+	{"(matr|vert|ind)(?:ix|ex)$", "${1}ices"},
+	{"^(m|l)ouse$", "${1}ice"},
+	{"^(m|l)ice$", "${1}ice"},
+	{"^(ox)$", "${1}en"},
+	{"^(oxen)$", "${1}"},
+	{"(quiz)$", "${1}zes"},
+	{"(drive)$", "${1}s"},
+}
+
+// Singularization rules of github.com/jinzhu/inflection. Later rules take precedence over the earlier ones
+var singularRules = [][2]string{
+	{"s$", ""},
+	{"(ss)$", "${1}"},
+	{"(n)ews$", "${1}ews"},
+	{"([ti])a$", "${1}um"},
+	{"((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$", "${1}sis"},
+	{"(^analy)(sis|ses)$", "${1}sis"},
+	{"([^f])ves$", "${1}fe"},
+	{"(hive)s$", "${1}"},
+	{"(tive)s$", "${1}"},
+	{"([lr])ves$", "${1}f"},
+	{"([^aeiouy]|qu)ies$", "${1}y"},
+	{"(s)eries$", "${1}eries"},
+	{"(m)ovies$", "${1}ovie"},
+	{"(x|ch|ss|sh)es$", "${1}"},
+	{"^(m|l)ice$", "${1}ouse"},
+	{"(bus)(es)?$", "${1}"},
+	{"(o)es$", "${1}"},
+	{"(shoe)s$", "${1}"},
+	{"(cris|test)(is|es)$", "${1}is"},
+	{"^(a)x[ie]s$", "${1}xis"},
+	{"(octop|vir)(us|i)$", "${1}us"},
+	{"(alias|status|campus)(es)?$", "${1}"},
+	{"^(ox)en", "${1}"},
+	{"(vert|ind)ices$", "${1}ex"},
+	{"(matr)ices$", "${1}ix"},
+	{"(quiz)zes$", "${1}"},
+	{"(database)s$", "${1}"},
+}
+
+var irregularPlurals = [][2]string{
+	{"person", "people"},
+	{"man", "men"},
+	{"child", "children"},
+	{"sex", "sexes"},
+	{"move", "moves"},
+	{"mombie", "mombies"},
+}
+
+var uncountables = []string{"equipment", "information", "rice", "money", "species", "series", "fish", "sheep", "jeans", "police"}
+
+type inflection struct {
+	regexp  *regexp.Regexp
+	replace string
+}
+
+// Compiled rules in the order they are tried
+var (
+	pluralInflections   = compileInflections(pluralRules, false)
+	singularInflections = compileInflections(singularRules, true)
+)
+
+// Compiles the rules after the uncountable and irregular words. Irregular words are reversed for singular rules
+func compileInflections(rules [][2]string, singular bool) []inflection {
+	var inflections []inflection
+	for _, word := range uncountables {
+		inflections = append(inflections, inflection{regexp.MustCompile("^(?i)(" + word + ")$"), "${1}"})
+	}
+
+	for _, irregular := range irregularPlurals {
+		if singular {
+			irregular[0], irregular[1] = irregular[1], irregular[0]
+		}
+		inflections = append(inflections,
+			inflection{regexp.MustCompile(strings.ToUpper(irregular[0]) + "$"), strings.ToUpper(irregular[1])},
+			inflection{regexp.MustCompile(strings.ToUpper(irregular[0][:1]) + irregular[0][1:] + "$"), strings.ToUpper(irregular[1][:1]) + irregular[1][1:]},
+			inflection{regexp.MustCompile(irregular[0] + "$"), irregular[1]},
+		)
+	}
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		rule := rules[i]
+		inflections = append(inflections,
+			inflection{regexp.MustCompile(strings.ToUpper(rule[0])), strings.ToUpper(rule[1])},
+			inflection{regexp.MustCompile(rule[0]), rule[1]},
+			inflection{regexp.MustCompile("(?i)" + rule[0]), rule[1]},
+		)
+	}
+	return inflections
+}
+
+// Returns the plural form of the given english word, same as gorm does for table names.
+// e.g. "person" -> "people", "status" -> "statuses", "bus" -> "buses"
+func Plural(word string) string {
+	for _, inflection := range pluralInflections {
+		if inflection.regexp.MatchString(word) {
+			return inflection.regexp.ReplaceAllString(word, inflection.replace)
+		}
+	}
+	return word
+}
+
+// Returns the singular form of the given english word, same as gorm does for the join table columns of a relation.
+// e.g. "people" -> "person", "Friends" -> "Friend"
+func Singular(word string) string {
+	for _, inflection := range singularInflections {
+		if inflection.regexp.MatchString(word) {
+			return inflection.regexp.ReplaceAllString(word, inflection.replace)
+		}
+	}
+	return word
+}
@@ -10,18 +10,32 @@ func ToMysqlDataType(s string) (string, error) {
 	switch strings.TrimSpace(s) {
 	case "string":
 		return "varchar(255)", nil
+	case "int8":
+		return "tinyint", nil
+	case "int16":
+		return "smallint", nil
 	case "int", "int32", "int64":
 		return "bigint", nil
+	case "uint8":
+		return "tinyint unsigned", nil
+	case "uint16":
+		return "smallint unsigned", nil
 	case "uint", "uint32", "uint64":
 		return "bigint unsigned", nil
 	case "float32":
 		return "float", nil
 	case "float64":
 		return "double", nil
-	case "time.Time":
+	case "time.Time", "gorm.DeletedAt":
 		return "datetime(3)", nil
 	case "bool":
 		return "tinyint(1)", nil
+	case "[]byte":
+		return "longblob", nil
+	case "decimal":
+		return "decimal(65,30)", nil
+	case "uuid":
+		return "char(36)", nil
 	}
 	return "", fmt.Errorf("Cannot convert %s to mysql type, explicit definition in tags required!", s)
 }
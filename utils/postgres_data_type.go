@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type names are written the way postgres 'format_type' reports them
+func ToPostgresDataType(s string) (string, error) {
+
+	switch strings.TrimSpace(s) {
+	case "string":
+		return "text", nil
+	case "int8", "int16", "uint8":
+		return "smallint", nil
+	case "uint16":
+		return "integer", nil
+	case "int", "int32", "int64":
+		return "bigint", nil
+	case "uint32":
+		return "bigint", nil
+	case "uint", "uint64":
+		// bigint is signed, it can't hold the values above 2^63-1
+		return "numeric(20)", nil
+	case "float32":
+		return "real", nil
+	case "float64":
+		return "double precision", nil
+	case "time.Time", "gorm.DeletedAt":
+		return "timestamp(3) with time zone", nil
+	case "bool":
+		return "boolean", nil
+	case "[]byte":
+		return "bytea", nil
+	case "decimal":
+		return "numeric", nil
+	case "uuid":
+		return "uuid", nil
+	}
+	return "", fmt.Errorf("Cannot convert %s to postgres type, explicit definition in tags required!", s)
+}
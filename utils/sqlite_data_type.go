@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ToSqliteDataType(s string) (string, error) {
+
+	switch strings.TrimSpace(s) {
+	case "string":
+		return "text", nil
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer", nil
+	case "float32", "float64":
+		return "real", nil
+	case "time.Time", "gorm.DeletedAt":
+		return "datetime", nil
+	case "bool":
+		return "boolean", nil
+	case "[]byte":
+		return "blob", nil
+	case "decimal":
+		return "numeric", nil
+	case "uuid":
+		return "text", nil
+	}
+	return "", fmt.Errorf("Cannot convert %s to sqlite type, explicit definition in tags required!", s)
+}
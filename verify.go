@@ -0,0 +1,141 @@
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/AkifSahn/migrator/schema"
+	"strings"
+)
+
+// Runs the new migration on the shadow database of the options before it is saved:
+//   - the saved migrations up to the current version are applied, then the up script
+//   - the shadow schema must need no more migrations to match the models
+//   - the down script is applied and the shadow schema must be the same as before the up script
+//
+// Shadow database must be empty, every table in it is dropped after the verification.
+// Failures are returned as '*VerifyError'
+func (m *Migrator) verifyMigration(ctx context.Context, opts Options, upScript, downScript string, targetModels ...interface{}) (err error) {
+	db, err := sql.Open(m.Dialect.DriverName(), opts.ShadowDSN)
+	if err != nil {
+		return fmt.Errorf("opening shadow database: %w", err)
+	}
+	defer db.Close()
+
+	// Statements run on a single connection, so session settings are kept and in memory databases are not lost
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("pinging shadow database: %w", err)
+	}
+
+	shadow := &Migrator{
+		DB:               db,
+		Dialect:          m.Dialect,
+		SchemaName:       cmp.Or(opts.ShadowSchema, m.SchemaName),
+		Relations:        make([]schema.Reference, 0),
+		Naming:           m.Naming,
+		AllowDestructive: true,
+		IncludeTables:    m.IncludeTables,
+		ExcludeTables:    m.ExcludeTables,
+		DataTypes:        m.DataTypes,
+		GormDB:           m.GormDB,
+	}
+
+	names, err := m.Dialect.TableNames(ctx, db, shadow.SchemaName)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("shadow database is not empty, it has the tables: %s", strings.Join(names, ", "))
+	}
+	defer func() {
+		if dropErr := shadow.dropAllTables(ctx); err == nil && dropErr != nil {
+			err = fmt.Errorf("cleaning shadow database: %w", dropErr)
+		}
+	}()
+
+	migrations, err := m.readSavedMigrations(cmp.Or(opts.Dir, m.MigrationsDir))
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if migration.Version > m.CurrentVersion {
+			break
+		}
+		if err := shadow.execScript(ctx, fmt.Sprintf("migration %d_%s", migration.Version, migration.Name), migration.Up); err != nil {
+			return err
+		}
+	}
+
+	before, _, err := shadow.getTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := shadow.execScript(ctx, "up script", upScript); err != nil {
+		return err
+	}
+	dst, err := shadow.ParseTablesFromStructs(targetModels...)
+	if err != nil {
+		return err
+	}
+	if err := shadow.expectSchema(ctx, "up script", dst); err != nil {
+		return err
+	}
+
+	if err := shadow.execScript(ctx, "down script", downScript); err != nil {
+		return err
+	}
+	return shadow.expectSchema(ctx, "down script", before)
+}
+
+// Runs the statements of the script one by one. The failing statement is returned as '*VerifyError'
+func (m *Migrator) execScript(ctx context.Context, step, script string) error {
+	statements, err := m.Dialect.SplitStatements(script)
+	if err != nil {
+		return &VerifyError{Step: step, Err: err}
+	}
+	for _, statement := range statements {
+		if err := execStatement(ctx, m.DB, statement); err != nil {
+			return &VerifyError{Step: step, Statement: statement, Err: err}
+		}
+	}
+	return nil
+}
+
+// Returns '*VerifyError' with the remaining migration if the database schema is not the given one
+func (m *Migrator) expectSchema(ctx context.Context, step string, tables []*schema.Table) error {
+	diff, _, err := m.createMigration(ctx, "", tables)
+	if err != nil {
+		return err
+	}
+	if diff != "" {
+		return &VerifyError{Step: step, Diff: diff}
+	}
+	return nil
+}
+
+// Drops every table of the database, referencing tables before the tables they reference
+func (m *Migrator) dropAllTables(ctx context.Context) error {
+	names, err := m.Dialect.TableNames(ctx, m.DB, m.SchemaName)
+	if err != nil {
+		return err
+	}
+
+	var tables []*schema.Table
+	for _, name := range names {
+		references, err := m.Dialect.GetReferences(ctx, m.DB, m.SchemaName, name)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, &schema.Table{Name: name, References: references})
+	}
+
+	var sb strings.Builder
+	sorted, deferred := sortTablesByDependency(tables)
+	if err := m.dropTables(sorted, deferred, &sb); err != nil {
+		return err
+	}
+	return m.execScript(ctx, "cleanup", sb.String())
+}
@@ -0,0 +1,111 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Driver that records the executed statements instead of running them. Statements containing "fail" fail
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+var recorder = &recordingDriver{}
+
+func init() {
+	sql.Register("migrator_recorder", recorder)
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return recordingConn{d}, nil
+}
+
+func (d *recordingDriver) reset() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	statements := d.statements
+	d.statements = nil
+	return statements
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("statement failed")
+	}
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.statements = append(c.driver.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (recordingConn) Close() error { return nil }
+
+func (recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func TestVerifyScriptStatements(t *testing.T) {
+	db, err := sql.Open("migrator_recorder", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	function := `CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+	NEW.updated_at = now();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql`
+	trigger := `CREATE TRIGGER touch AFTER UPDATE ON users
+BEGIN
+	UPDATE users SET updated = 1 WHERE id = NEW.id;
+END`
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		script  string
+		want    []string
+	}{
+		{"postgres dollar quoted function", PostgresDialect{}, function + ";\nALTER TABLE users ADD COLUMN path text DEFAULT 'C:\\';\n",
+			[]string{function, `ALTER TABLE users ADD COLUMN path text DEFAULT 'C:\'`}},
+		{"sqlite trigger", SQLiteDialect{}, trigger + ";\nINSERT INTO tags VALUES ('#1');\n",
+			[]string{trigger, "INSERT INTO tags VALUES ('#1')"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder.reset()
+			shadow := &Migrator{DB: db, Dialect: test.dialect}
+			if err := shadow.execScript(context.Background(), "up script", test.script); err != nil {
+				t.Fatal(err)
+			}
+			if got := recorder.reset(); !slices.Equal(got, test.want) {
+				t.Errorf("executed statements %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	shadow := &Migrator{DB: db, Dialect: PostgresDialect{}}
+	err = shadow.execScript(context.Background(), "down script", "SELECT 'a\\'; SELECT fail();")
+	var verifyErr *VerifyError
+	if !errors.As(err, &verifyErr) || verifyErr.Step != "down script" || verifyErr.Statement != "SELECT fail()" {
+		t.Errorf("failing statement is reported as %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Versioning decides the version of a new migration
+type Versioning int
+
+const (
+	// Version after the current version of the database. Offline migrators use the latest migration file instead
+	SEQUENTIAL_VERSIONING Versioning = iota
+
+	// Version after the latest migration file in the save directory, the database version is not used
+	DIRECTORY_VERSIONING
+
+	// UTC creation time of the migration, e.g. 20261017120000. Migrations of different branches don't collide
+	TIMESTAMP_VERSIONING
+)
+
+// Layout of the timestamp versions
+const TIMESTAMP_FORMAT = "20060102150405"
+
+// Smallest timestamp version, sequential versions are below it
+const MIN_TIMESTAMP_VERSION int64 = 10000000000000
+
+// Checks the versions of the migration files in the given directory, in the format of the given writer.
+// Returns '*VersionError' if a version is used by more than one migration or a sequential version is skipped.
+// Timestamp versions are only checked for duplicates
+func CheckVersions(dir string, writer MigrationWriter) error {
+	migrations, err := writer.ReadMigrations(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if gaps := versionGaps(migrationVersions(migrations)); len(gaps) > 0 {
+		return &VersionError{Dir: dir, Gaps: gaps}
+	}
+	return nil
+}
+
+// Returns the version of the new migration for the given versioning.
+// Versions are the ordered versions of the migrations already in the save directory
+func (m *Migrator) nextVersion(versioning Versioning, versions []int64) (int64, error) {
+	var latest int64
+	if len(versions) > 0 {
+		latest = versions[len(versions)-1]
+	}
+
+	switch versioning {
+	case SEQUENTIAL_VERSIONING:
+		version := m.CurrentVersion + 1
+		if version <= latest {
+			return 0, fmt.Errorf("version %d is not after the latest migration file %d, the database may be behind the migration directory. "+
+				"Apply the migrations or use DIRECTORY_VERSIONING", version, latest)
+		}
+		return version, nil
+
+	case DIRECTORY_VERSIONING:
+		return latest + 1, nil
+
+	case TIMESTAMP_VERSIONING:
+		version, err := strconv.ParseInt(time.Now().UTC().Format(TIMESTAMP_FORMAT), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		// Migrations created in the same second still get different versions
+		return max(version, latest+1), nil
+	}
+
+	return 0, fmt.Errorf("unknown versioning %d", versioning)
+}
+
+// Returns the ranges of the skipped versions between the given ordered sequential versions.
+// Versions before the first migration are not reported, older migrations may have been squashed
+func versionGaps(versions []int64) [][2]int64 {
+	var gaps [][2]int64
+	for i := 1; i < len(versions); i++ {
+		previous, version := versions[i-1], versions[i]
+		if version >= MIN_TIMESTAMP_VERSION {
+			break
+		}
+		if version > previous+1 {
+			gaps = append(gaps, [2]int64{previous + 1, version - 1})
+		}
+	}
+	return gaps
+}
@@ -0,0 +1,212 @@
+package migrator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Migration is a versioned pair of up and down scripts
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // Empty if the format doesn't keep down scripts
+}
+
+// MigrationWriter reads and writes the migration files of a directory in the format of a migration tool
+type MigrationWriter interface {
+	// Returns the migrations saved in the directory ordered by version.
+	// '*VersionError' is returned if more than one migration has the same version
+	ReadMigrations(dir string) ([]Migration, error)
+
+	// Saves the migration into the directory. Existing files are replaced only if overwrite is true.
+	// Returns the paths of the written files
+	WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error)
+}
+
+// GolangMigrateWriter saves migrations as "<version>_<name>.up.sql" and "<version>_<name>.down.sql" files.
+// It is the default format of migrator
+type GolangMigrateWriter struct{}
+
+var _ MigrationWriter = GolangMigrateWriter{}
+
+func (GolangMigrateWriter) ReadMigrations(dir string) ([]Migration, error) {
+	files, err := readMigrationFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		migration := Migration{Version: file.Version, Name: file.Name}
+		if file.UpPath == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", file.Version, file.Name)
+		}
+		if migration.Up, err = readFile(file.UpPath); err != nil {
+			return nil, err
+		}
+		if file.DownPath != "" {
+			if migration.Down, err = readFile(file.DownPath); err != nil {
+				return nil, err
+			}
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+func (GolangMigrateWriter) WriteMigration(dir string, migration Migration, overwrite bool) ([]string, error) {
+	name := fmt.Sprintf("%d_%s", migration.Version, migration.Name)
+	return writeFiles([]outputFile{
+		{Path: filepath.Join(dir, name+".up.sql"), Content: migration.Up},
+		{Path: filepath.Join(dir, name+".down.sql"), Content: migration.Down},
+	}, overwrite)
+}
+
+// Returns the writer of the saved migration files, golang-migrate if not set
+func (m *Migrator) writer() MigrationWriter {
+	if m.Writer == nil {
+		return GolangMigrateWriter{}
+	}
+	return m.Writer
+}
+
+// Returns the versions of the given ordered migrations
+func migrationVersions(migrations []Migration) []int64 {
+	versions := make([]int64, 0, len(migrations))
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+type outputFile struct {
+	Path    string
+	Content string
+}
+
+// Writes the given files. Unless overwrite is true, every file is checked before writing,
+// so an existing file doesn't leave half of a migration behind
+func writeFiles(files []outputFile, overwrite bool) ([]string, error) {
+	if !overwrite {
+		for _, file := range files {
+			if _, err := os.Stat(file.Path); err == nil {
+				return nil, fmt.Errorf("migration file %s already exists", file.Path)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
+			return nil, fmt.Errorf("writing migration file: %w", err)
+		}
+		paths = append(paths, file.Path)
+	}
+	return paths, nil
+}
+
+func readFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Matches single file migrations. e.g. "3_add_users.sql"
+var singleFileRegexp = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+
+// Reads the single file migrations of the directory. Each file is split into the up and down scripts by the given function
+func readSingleFileMigrations(dir string, split func(path, content string) (string, string, error)) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migration directory: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := singleFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing version of %s: %w", entry.Name(), err)
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		up, down, err := split(path, content)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], Up: up, Down: down})
+	}
+
+	return sortMigrations(dir, migrations)
+}
+
+// Orders the migrations by version. Returns '*VersionError' if a version is used by more than one migration
+func sortMigrations(dir string, migrations []Migration) ([]Migration, error) {
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+
+	var duplicates []int64
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version && !slices.Contains(duplicates, migrations[i].Version) {
+			duplicates = append(duplicates, migrations[i].Version)
+		}
+	}
+	if len(duplicates) > 0 {
+		return nil, &VersionError{Dir: dir, Duplicates: duplicates}
+	}
+	return migrations, nil
+}
+
+// Splits the content of a single file migration by the lines starting with the up and down annotations.
+// e.g. "-- +goose Up" and "-- +goose Down"
+func splitAnnotated(path, content, upAnnotation, downAnnotation string) (string, string, error) {
+	var up, down strings.Builder
+	var current *strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, upAnnotation):
+			current = &up
+		case strings.HasPrefix(trimmed, downAnnotation):
+			current = &down
+		case current != nil:
+			current.WriteString(line)
+		case trimmed != "" && !strings.HasPrefix(trimmed, "--"):
+			return "", "", fmt.Errorf("%s: statement before the %q annotation", path, upAnnotation)
+		}
+	}
+	if current == nil {
+		return "", "", fmt.Errorf("%s: missing %q annotation", path, upAnnotation)
+	}
+	return trimScript(up.String()), trimScript(down.String()), nil
+}
+
+// Removes the blank lines around the script, keeping the new line at the end
+func trimScript(script string) string {
+	script = strings.TrimSpace(script)
+	if script == "" {
+		return ""
+	}
+	return script + "\n"
+}